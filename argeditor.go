@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// argsEdited is sent when the external editor opened with Ctrl+O exits.
+type argsEdited struct {
+	path string
+	err  error
+}
+
+// formArgsJSON returns the arguments in the form as indented JSON.
+func (m *AppModel) formArgsJSON() string {
+	args, _ := m.argForm.Value()
+	data, err := json.MarshalIndent(args, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data)
+}
+
+// parseArgsJSON parses the text of the JSON editor. Empty text means no
+// arguments.
+func parseArgsJSON(text string) (map[string]any, error) {
+	args := map[string]any{}
+	if strings.TrimSpace(text) == "" {
+		return args, nil
+	}
+	if err := json.Unmarshal([]byte(text), &args); err != nil {
+		return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+	return args, nil
+}
+
+// openArgumentEditor switches the argument form to a JSON editor holding the
+// arguments entered so far.
+func (m *AppModel) openArgumentEditor() tea.Cmd {
+	return m.showArgumentEditor(m.formArgsJSON())
+}
+
+func (m *AppModel) showArgumentEditor(text string) tea.Cmd {
+	ta := textarea.New()
+	ta.CharLimit = 0
+	ta.SetHeight(15)
+	ta.SetValue(text)
+	m.argEditor = ta
+	m.argEditorErr = ""
+	m.argRawMode = true
+	return m.argEditor.Focus()
+}
+
+func (m *AppModel) updateArgumentEditor(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+t":
+			args, err := parseArgsJSON(m.argEditor.Value())
+			if err != nil {
+				m.argEditorErr = err.Error()
+				return m, nil
+			}
+			m.argForm.SetValue(args)
+			m.argRawMode = false
+			return m, nil
+		case "ctrl+o":
+			return m, m.editArgsExternally()
+		case "ctrl+s":
+			args, err := parseArgsJSON(m.argEditor.Value())
+			if err == nil && !noValidate {
+				err = validateArgs(m.selectedTool.InputSchema, args)
+			}
+			if err != nil {
+				m.argEditorErr = err.Error()
+				return m, nil
+			}
+			m.argEditorErr = ""
+			m.argForm.SetValue(args)
+			return m.callTool(args)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.argEditor, cmd = m.argEditor.Update(msg)
+	return m, cmd
+}
+
+// editArgsExternally writes the arguments to a temporary file and opens it
+// in $VISUAL or $EDITOR, falling back to vi.
+func (m *AppModel) editArgsExternally() tea.Cmd {
+	text := m.formArgsJSON()
+	if m.argRawMode {
+		text = m.argEditor.Value()
+	}
+
+	f, err := os.CreateTemp("", "mcp-cli-args-*.json")
+	if err != nil {
+		m.logf("Failed to create temporary file: %v", err)
+		return nil
+	}
+	_, err = f.WriteString(text)
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		m.logf("Failed to write temporary file: %v", err)
+		return nil
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], f.Name())...)
+	path := f.Name()
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return argsEdited{path: path, err: err}
+	})
+}
+
+// applyEditedArgs loads the arguments saved in the external editor into the
+// form. If they do not parse, they are shown in the JSON editor instead.
+func (m *AppModel) applyEditedArgs(msg argsEdited) tea.Cmd {
+	defer os.Remove(msg.path)
+	if msg.err != nil {
+		m.logf("Editor failed: %v", msg.err)
+		return nil
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.logf("Failed to read edited arguments: %v", err)
+		return nil
+	}
+
+	args, err := parseArgsJSON(string(data))
+	if err != nil {
+		cmd := m.showArgumentEditor(string(data))
+		m.argEditorErr = err.Error()
+		return cmd
+	}
+	m.argForm.SetValue(args)
+	m.argRawMode = false
+	m.logf("Arguments updated from editor")
+	return nil
+}
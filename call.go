@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var callCmd = &cobra.Command{
+	Use:   "call [transport] [target] [tool]",
+	Short: "Call a tool without the TUI and print its result",
+	Long: `Call a single tool and print the result to stdout.
+
+The transport is one of stdio, sse or http. The target is the command to start
+for stdio, or the server URL for sse and http. The command exits with a non-zero
+status when the call fails or the tool reports an error.`,
+	Example: `  mcp-cli call stdio "python server.py" add --arg a=1 --arg b=2
+  mcp-cli call http http://localhost:8080/mcp search --args-json '{"query": "mcp"}'`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, target, toolName := args[0], args[1], args[2]
+		argStrings, _ := cmd.Flags().GetStringArray("arg")
+		argsJSON, _ := cmd.Flags().GetString("args-json")
+		saveDir, _ := cmd.Flags().GetString("save-dir")
+
+		ctx := context.Background()
+		session, err := connectHeadless(ctx, cmd, kind, target)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s server: %v", kind, err)
+		}
+
+		reqCtx, cancel := newRequestContext(ctx)
+		tool, result, err := callToolHeadless(reqCtx, session, toolName, argStrings, argsJSON)
+		cancel()
+		session.Close()
+		if err != nil {
+			log.Fatalf("Failed to call tool '%s': %v", toolName, err)
+		}
+
+		fmt.Println(formatToolResult(result))
+		if saveDir != "" {
+			paths, err := saveAttachments(result, toolName, saveDir)
+			for _, path := range paths {
+				log.Printf("Saved %s", path)
+			}
+			if err != nil {
+				log.Fatalf("Failed to save attachments: %v", err)
+			}
+		}
+		structured := checkStructuredContent(tool, result)
+		if verbose || len(structured.violations) > 0 || len(structured.warnings) > 0 {
+			for _, line := range structured.summary() {
+				log.Print(line)
+			}
+		}
+		if result.IsError {
+			os.Exit(1)
+		}
+	},
+}
+
+// callToolHeadless looks up the named tool, builds its arguments from the
+// command line and calls it.
+func callToolHeadless(ctx context.Context, session *mcp.ClientSession, toolName string, argStrings []string, argsJSON string) (*mcp.Tool, *mcp.CallToolResult, error) {
+	tool, err := findTool(ctx, session, toolName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, err := buildToolArgs(tool, argStrings, argsJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := callToolArgs(ctx, session, tool, args)
+	return tool, result, err
+}
+
+// callToolArgs validates args against the tool's input schema, unless
+// --no-validate is given, and calls the tool.
+func callToolArgs(ctx context.Context, session *mcp.ClientSession, tool *mcp.Tool, args map[string]any) (*mcp.CallToolResult, error) {
+	if !noValidate {
+		if err := validateArgs(tool.InputSchema, args); err != nil {
+			return nil, err
+		}
+	}
+
+	if verbose {
+		prettyArgs, _ := json.MarshalIndent(args, "", "  ")
+		log.Printf("Calling tool '%s' with args:\n%s", tool.Name, prettyArgs)
+	}
+
+	return session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      tool.Name,
+		Arguments: args,
+	})
+}
+
+// findTool returns the tool with the given name from the server's tool list.
+func findTool(ctx context.Context, session *mcp.ClientSession, name string) (*mcp.Tool, error) {
+	for tool, err := range session.Tools(ctx, nil) {
+		if err != nil {
+			return nil, err
+		}
+		if tool.Name == name {
+			return tool, nil
+		}
+	}
+	return nil, fmt.Errorf("tool '%s' not found", name)
+}
+
+// buildToolArgs merges the JSON object in argsJSON with the key=value pairs in
+// argStrings. Pairs are coerced using the tool's input schema and take
+// precedence over keys from argsJSON.
+func buildToolArgs(tool *mcp.Tool, argStrings []string, argsJSON string) (map[string]any, error) {
+	args := make(map[string]any)
+	if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+			return nil, fmt.Errorf("invalid --args-json: %w", err)
+		}
+	}
+
+	for _, a := range argStrings {
+		name, valueStr, ok := strings.Cut(a, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --arg %q, expected key=value", a)
+		}
+
+		var value any = valueStr
+		if tool.InputSchema != nil {
+			prop := tool.InputSchema.Properties[name]
+			v, err := coerceArg(prop, valueStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for arg '%s' (%s): %w", name, prop.Type, err)
+			}
+			value = v
+		}
+		args[name] = value
+	}
+
+	return args, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var callStatusStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("214")) // Orange
+
+// newRequestContext returns a context for a single request, bounded by the
+// --timeout flag when it is set.
+func newRequestContext(parent context.Context) (context.Context, context.CancelFunc) {
+	if requestTimeout > 0 {
+		return context.WithTimeout(parent, requestTimeout)
+	}
+	return context.WithCancel(parent)
+}
+
+// pendingRequest is a request that can be cancelled from the TUI.
+type pendingRequest struct {
+	kind   string
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// startRequest returns a context for a request of the given kind ("tool",
+// "resource" or "prompt") and remembers it so that it can be cancelled. The
+// caller must call the returned cancel func once the request returns.
+// Requests are tracked one by one, so a call started while another is still
+// running does not stop the first from being cancelled.
+func (m *AppModel) startRequest(kind string) (context.Context, context.CancelFunc) {
+	// Forget requests that have returned, whose contexts their callers have
+	// cancelled.
+	maps.DeleteFunc(m.pending, func(_ int, r pendingRequest) bool { return r.ctx.Err() != nil })
+
+	ctx, cancel := newRequestContext(m.ctx)
+	m.requestSeq++
+	m.pending[m.requestSeq] = pendingRequest{kind: kind, ctx: ctx, cancel: cancel}
+	return ctx, cancel
+}
+
+// cancelRequests cancels every request that is still waiting for a response.
+// The SDK notifies the server with notifications/cancelled.
+func (m *AppModel) cancelRequests() {
+	cancelled := false
+	for _, id := range slices.Sorted(maps.Keys(m.pending)) {
+		if r := m.pending[id]; r.ctx.Err() == nil {
+			m.logf("Cancelling %s request", r.kind)
+			r.cancel()
+			cancelled = true
+		}
+	}
+	if !cancelled {
+		m.logf("No request to cancel")
+	}
+}
+
+// requestStatus describes a request that ended because it was cancelled or
+// timed out. It returns "" for any other error.
+func requestStatus(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "Cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return fmt.Sprintf("Timed out after %s", requestTimeout)
+	}
+	return ""
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/jsonschema-go/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// presetCollection is the collection file that presets saved in the TUI are
+// added to. It defaults to presets.yaml in the collections directory.
+var presetCollection string
+
+// collection is a named list of saved tool calls, stored as a YAML (or JSON)
+// file that can be shared and run as a batch with the run command. String
+// arguments may refer to variables as {{name}}.
+type collection struct {
+	Name      string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty" json:"variables,omitempty"`
+	Requests  []*savedRequest   `yaml:"requests" json:"requests"`
+
+	path string
+}
+
+// savedRequest is a tool call saved in a collection. In the TUI, the saved
+// requests of a tool are its presets.
+type savedRequest struct {
+	Name      string         `yaml:"name" json:"name"`
+	Tool      string         `yaml:"tool" json:"tool"`
+	Arguments map[string]any `yaml:"arguments,omitempty" json:"arguments,omitempty"`
+}
+
+// collectionsDir returns the directory that the TUI loads presets from.
+func collectionsDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mcp-cli", "collections"), nil
+}
+
+// presetCollectionPath returns the file that new presets are saved to.
+func presetCollectionPath() (string, error) {
+	if presetCollection != "" {
+		return presetCollection, nil
+	}
+	dir, err := collectionsDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "presets.yaml"), nil
+}
+
+// loadCollection reads the collection at path. A missing file is an empty
+// collection.
+func loadCollection(path string) (*collection, error) {
+	c := &collection{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i, r := range c.Requests {
+		if r == nil || r.Tool == "" {
+			return nil, fmt.Errorf("%s: request %d has no tool", path, i+1)
+		}
+	}
+	return c, nil
+}
+
+// loadCollections reads every collection in the collections directory and
+// the preset collection, which may live elsewhere.
+func loadCollections() ([]*collection, error) {
+	dir, err := collectionsDir()
+	if err != nil {
+		return nil, err
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	more, _ := filepath.Glob(filepath.Join(dir, "*.yml"))
+	paths = append(paths, more...)
+	more, _ = filepath.Glob(filepath.Join(dir, "*.json"))
+	paths = append(paths, more...)
+	if path, err := presetCollectionPath(); err == nil && !slices.Contains(paths, path) {
+		paths = append(paths, path)
+	}
+
+	var collections []*collection
+	var errs []error
+	for _, path := range paths {
+		c, err := loadCollection(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		collections = append(collections, c)
+	}
+	return collections, errors.Join(errs...)
+}
+
+// save writes the collection back to its file, as JSON if the file name ends
+// in .json and as YAML otherwise. Comments in the file are not kept.
+func (c *collection) save() error {
+	var data []byte
+	if strings.EqualFold(filepath.Ext(c.path), ".json") {
+		var err error
+		if data, err = json.MarshalIndent(c, "", "  "); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+		data = buf.Bytes()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
+
+// put adds r to the collection, replacing a request for the same tool with
+// the same name.
+func (c *collection) put(r *savedRequest) {
+	for i, old := range c.Requests {
+		if old.Tool == r.Tool && old.Name == r.Name {
+			c.Requests[i] = r
+			return
+		}
+	}
+	c.Requests = append(c.Requests, r)
+}
+
+// remove deletes r from the collection.
+func (c *collection) remove(r *savedRequest) {
+	c.Requests = slices.DeleteFunc(c.Requests, func(old *savedRequest) bool { return old == r })
+}
+
+// displayName names the collection after its name field or its file.
+func (c *collection) displayName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return strings.TrimSuffix(filepath.Base(c.path), filepath.Ext(c.path))
+}
+
+var variableRef = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// arguments returns the arguments of r with {{name}} references in string
+// values replaced by the collection's variables, overridden by vars. A value
+// that is a single reference takes the type that schema, the tool's input
+// schema, gives the argument, so that "{{count}}" can fill in an integer. The
+// result has plain JSON types, as if it had been decoded from JSON.
+func (c *collection) arguments(r *savedRequest, vars map[string]string, schema *jsonschema.Schema) (map[string]any, error) {
+	var missing []string
+	var errs []error
+	var expand func(v any, schema *jsonschema.Schema) any
+	expand = func(v any, schema *jsonschema.Schema) any {
+		switch v := v.(type) {
+		case string:
+			expanded := variableRef.ReplaceAllStringFunc(v, func(ref string) string {
+				name := variableRef.FindStringSubmatch(ref)[1]
+				if value, ok := vars[name]; ok {
+					return value
+				}
+				if value, ok := c.Variables[name]; ok {
+					return value
+				}
+				missing = append(missing, name)
+				return ref
+			})
+			if loc := variableRef.FindStringIndex(v); loc == nil || loc[0] != 0 || loc[1] != len(v) {
+				return expanded
+			}
+			value, err := coerceArg(schema, expanded)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s is %q: %w", v, expanded, err))
+				return expanded
+			}
+			return value
+		case map[string]any:
+			out := make(map[string]any, len(v))
+			for k, e := range v {
+				var prop *jsonschema.Schema
+				if schema != nil {
+					prop = schema.Properties[k]
+				}
+				out[k] = expand(e, prop)
+			}
+			return out
+		case []any:
+			out := make([]any, len(v))
+			for i, e := range v {
+				var items *jsonschema.Schema
+				if schema != nil {
+					items = schema.Items
+				}
+				out[i] = expand(e, items)
+			}
+			return out
+		}
+		return v
+	}
+
+	expanded := expand(r.Arguments, schema)
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("undefined variables: %s", strings.Join(slices.Compact(missing), ", "))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	data, err := json.Marshal(expanded)
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]any{}
+	if err := json.Unmarshal(data, &args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+type presetItem struct {
+	collection *collection
+	request    *savedRequest
+}
+
+func (i presetItem) Title() string { return i.request.Name }
+
+func (i presetItem) Description() string {
+	args, _ := json.Marshal(i.request.Arguments)
+	return fmt.Sprintf("%s  %s", i.collection.displayName(), args)
+}
+
+func (i presetItem) FilterValue() string { return i.request.Name }
+
+func newPresetList() list.Model {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "load")),
+			key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "save current")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "delete")),
+		}
+	}
+	return l
+}
+
+// openPresets loads the presets of the selected tool and shows the picker.
+func (m *AppModel) openPresets() tea.Cmd {
+	collections, err := loadCollections()
+	if err != nil {
+		m.logf("Failed to load collections: %v", err)
+	}
+	m.collections = collections
+	m.presetList.Title = fmt.Sprintf("Presets for %s", m.selectedTool.Name)
+	m.savingPreset = false
+	m.state = presetView
+	return m.presetList.SetItems(m.presetItems())
+}
+
+func (m *AppModel) presetItems() []list.Item {
+	items := []list.Item{}
+	for _, c := range m.collections {
+		for _, r := range c.Requests {
+			if r.Tool == m.selectedTool.Name {
+				items = append(items, presetItem{collection: c, request: r})
+			}
+		}
+	}
+	return items
+}
+
+func (m *AppModel) updatePresetView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.savingPreset {
+		return m.updatePresetInput(msg)
+	}
+
+	// Handle our keys before the list sees them, since "d" also pages the list.
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.presetList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.presetList, cmd = m.presetList.Update(msg)
+		return m, cmd
+	}
+	selected, _ := m.presetList.SelectedItem().(presetItem)
+	switch keyMsg.String() {
+	case "esc":
+		m.state = argumentInputView
+		return m, nil
+	case "enter":
+		if selected.request == nil {
+			return m, nil
+		}
+		args, err := selected.collection.arguments(selected.request, nil, m.selectedTool.InputSchema)
+		if err != nil {
+			m.logf("Cannot load preset '%s': %v", selected.request.Name, err)
+			return m, nil
+		}
+		m.argForm.SetValue(args)
+		m.state = argumentInputView
+		m.logf("Loaded preset '%s'", selected.request.Name)
+		return m, nil
+	case "n":
+		m.savingPreset = true
+		m.presetInput = textinput.New()
+		m.presetInput.Placeholder = "preset name"
+		m.presetInput.CharLimit = 256
+		m.presetInput.Width = 50
+		return m, m.presetInput.Focus()
+	case "d":
+		if selected.request == nil {
+			return m, nil
+		}
+		selected.collection.remove(selected.request)
+		if err := selected.collection.save(); err != nil {
+			m.logf("Failed to save %s: %v", selected.collection.path, err)
+			return m, nil
+		}
+		m.logf("Deleted preset '%s' from %s", selected.request.Name, selected.collection.path)
+		return m, m.presetList.SetItems(m.presetItems())
+	}
+
+	var cmd tea.Cmd
+	m.presetList, cmd = m.presetList.Update(msg)
+	return m, cmd
+}
+
+func (m *AppModel) updatePresetInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.savingPreset = false
+			return m, nil
+		case tea.KeyEnter:
+			name := strings.TrimSpace(m.presetInput.Value())
+			if name == "" {
+				return m, nil
+			}
+			m.savingPreset = false
+			return m, m.savePreset(name)
+		}
+	}
+	var cmd tea.Cmd
+	m.presetInput, cmd = m.presetInput.Update(msg)
+	return m, cmd
+}
+
+// savePreset saves the arguments in the form as a preset of the selected tool
+// in the preset collection.
+func (m *AppModel) savePreset(name string) tea.Cmd {
+	args, err := m.argForm.Value()
+	if err != nil {
+		m.logf("Cannot save preset: %v", err)
+		return nil
+	}
+	path, err := presetCollectionPath()
+	if err != nil {
+		m.logf("Cannot save preset: %v", err)
+		return nil
+	}
+
+	var c *collection
+	for _, loaded := range m.collections {
+		if loaded.path == path {
+			c = loaded
+		}
+	}
+	if c == nil {
+		// Do not overwrite a collection that failed to load.
+		if c, err = loadCollection(path); err != nil {
+			m.logf("Cannot save preset: %v", err)
+			return nil
+		}
+		m.collections = append(m.collections, c)
+	}
+	c.put(&savedRequest{Name: name, Tool: m.selectedTool.Name, Arguments: args})
+	if err := c.save(); err != nil {
+		m.logf("Failed to save %s: %v", path, err)
+		return nil
+	}
+	m.logf("Saved preset '%s' to %s", name, path)
+	return m.presetList.SetItems(m.presetItems())
+}
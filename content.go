@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Inline image previews in the result view are at most this many cells. Kitty
+// previews number their cells with kittyDiacritics, which must cover both.
+const (
+	previewCols = 60
+	previewRows = 20
+)
+
+// attachment is a content block of a tool result that is not text: an image,
+// an audio clip, a link to a resource or an embedded resource.
+type attachment struct {
+	content mcp.Content
+	img     image.Image // decoded image, or nil if it is not an image or cannot be decoded
+
+	// The inline preview is cached because the result view is rendered on
+	// every key press.
+	previewCols int
+	previewRows int
+	preview     string
+	kittyID     uint32 // id of the preview sent to kitty, or 0
+}
+
+func newAttachment(c mcp.Content) *attachment {
+	a := &attachment{content: c}
+	if img, ok := c.(*mcp.ImageContent); ok {
+		a.img, _, _ = image.Decode(bytes.NewReader(img.Data))
+	}
+	return a
+}
+
+// resultBlock is a content block of a tool result as shown in the result
+// view: either text or an attachment.
+type resultBlock struct {
+	text       string
+	attachment *attachment
+}
+
+// resultBlocks splits the content of result into text and attachments.
+func resultBlocks(result *mcp.CallToolResult) []resultBlock {
+	var blocks []resultBlock
+	if result.IsError {
+		blocks = append(blocks, resultBlock{text: "Error:\n"})
+	}
+	for _, c := range result.Content {
+		if _, ok := c.(*mcp.TextContent); ok {
+			blocks = append(blocks, resultBlock{text: formatToolContent(c)})
+		} else {
+			blocks = append(blocks, resultBlock{attachment: newAttachment(c)})
+		}
+	}
+	return blocks
+}
+
+// describeContent returns a one-line summary of a content block that is not
+// text.
+func describeContent(c mcp.Content) string {
+	switch c := c.(type) {
+	case *mcp.ImageContent:
+		desc := fmt.Sprintf("[image: %s, %s", c.MIMEType, formatSize(len(c.Data)))
+		if cfg, _, err := image.DecodeConfig(bytes.NewReader(c.Data)); err == nil {
+			desc += fmt.Sprintf(", %dx%d", cfg.Width, cfg.Height)
+		}
+		return desc + "]"
+	case *mcp.AudioContent:
+		return fmt.Sprintf("[audio: %s, %s]", c.MIMEType, formatSize(len(c.Data)))
+	case *mcp.ResourceLink:
+		return fmt.Sprintf("[resource link: %s (%s)]", c.Name, c.URI)
+	case *mcp.EmbeddedResource:
+		if c.Resource == nil {
+			return "[embedded resource]"
+		}
+		if c.Resource.Blob != nil {
+			return fmt.Sprintf("[embedded resource: %s, %s]", c.Resource.URI, formatSize(len(c.Resource.Blob)))
+		}
+		return fmt.Sprintf("[embedded resource: %s]", c.Resource.URI)
+	}
+	return fmt.Sprintf("Unsupported content type: %T", c)
+}
+
+// formatSize formats a number of bytes for display.
+func formatSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// data returns the bytes that can be saved to a file, or nil if the
+// attachment has none.
+func (a *attachment) data() []byte {
+	switch c := a.content.(type) {
+	case *mcp.ImageContent:
+		return c.Data
+	case *mcp.AudioContent:
+		return c.Data
+	case *mcp.EmbeddedResource:
+		if c.Resource == nil {
+			return nil
+		}
+		if c.Resource.Blob != nil {
+			return c.Resource.Blob
+		}
+		return []byte(c.Resource.Text)
+	}
+	return nil
+}
+
+func (a *attachment) mimeType() string {
+	switch c := a.content.(type) {
+	case *mcp.ImageContent:
+		return c.MIMEType
+	case *mcp.AudioContent:
+		return c.MIMEType
+	case *mcp.EmbeddedResource:
+		if c.Resource != nil && c.Resource.MIMEType != "" {
+			return c.Resource.MIMEType
+		}
+		if c.Resource != nil && c.Resource.Blob == nil {
+			return "text/plain"
+		}
+	}
+	return ""
+}
+
+// fileName returns a default file name for saving the attachment, made from
+// the tool name, the attachment's position and an extension for its MIME type.
+func (a *attachment) fileName(tool string, n int) string {
+	return fmt.Sprintf("%s-%d%s", tool, n, extensionFor(a.mimeType()))
+}
+
+// extensionFor returns a file extension for a MIME type. The common image and
+// audio types are listed because mime.ExtensionsByType returns them in no
+// useful order.
+func extensionFor(mimeType string) string {
+	mediaType, _, _ := mime.ParseMediaType(mimeType)
+	switch mediaType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/svg+xml":
+		return ".svg"
+	case "audio/wav", "audio/x-wav", "audio/wave":
+		return ".wav"
+	case "audio/mpeg":
+		return ".mp3"
+	case "audio/ogg":
+		return ".ogg"
+	case "text/plain":
+		return ".txt"
+	case "application/json":
+		return ".json"
+	}
+	if exts, _ := mime.ExtensionsByType(mediaType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ".bin"
+}
+
+// save writes the attachment's data to path.
+func (a *attachment) save(path string) error {
+	data := a.data()
+	if data == nil {
+		return fmt.Errorf("%s has no data to save", describeContent(a.content))
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// saveAttachments writes the data of every attachment in result to dir and
+// returns the paths of the files written.
+func saveAttachments(result *mcp.CallToolResult, tool, dir string) ([]string, error) {
+	var paths []string
+	n := 0
+	for _, c := range result.Content {
+		if _, ok := c.(*mcp.TextContent); ok {
+			continue
+		}
+		n++
+		a := newAttachment(c)
+		if a.data() == nil {
+			continue
+		}
+		path := filepath.Join(dir, a.fileName(tool, n))
+		if err := a.save(path); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// view renders the attachment as a line describing it, followed by a preview
+// for images. The line is highlighted when the attachment is selected.
+func (a *attachment) view(n int, selected bool, width int) string {
+	line := fmt.Sprintf("  %d. %s", n, describeContent(a.content))
+	if selected {
+		line = formFocusStyle.Render(fmt.Sprintf("> %d. %s", n, describeContent(a.content)))
+	}
+	if a.img == nil {
+		return line
+	}
+
+	if width <= 0 {
+		width = previewCols
+	}
+	cols, rows := fitCells(a.img.Bounds(), min(width, previewCols), previewRows)
+	if a.preview == "" || a.previewCols != cols || a.previewRows != rows {
+		if detectImageProtocol() == "kitty" {
+			if a.kittyID == 0 {
+				a.kittyID = newKittyImageID()
+			}
+			a.preview = kittyPreview(a.img, a.kittyID, cols, rows)
+		} else {
+			a.preview = halfBlocks(a.img, cols, rows)
+		}
+		a.previewCols, a.previewRows = cols, rows
+	}
+	return line + "\n" + a.preview
+}
+
+// attachments returns the attachments of the last tool result in order.
+func (m *AppModel) attachments() []*attachment {
+	var attachments []*attachment
+	for _, b := range m.resultBlocks {
+		if b.attachment != nil {
+			attachments = append(attachments, b.attachment)
+		}
+	}
+	return attachments
+}
+
+// resultContentView renders the content of the last tool result with the
+// selected attachment highlighted.
+func (m *AppModel) resultContentView(width int) string {
+	var b strings.Builder
+	n := 0
+	for _, block := range m.resultBlocks {
+		if block.attachment == nil {
+			b.WriteString(block.text)
+			continue
+		}
+		if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+			b.WriteString("\n")
+		}
+		b.WriteString(block.attachment.view(n+1, n == m.resultPane.selected, width))
+		b.WriteString("\n")
+		n++
+	}
+	return b.String()
+}
+
+// openAttachment shows the selected attachment: images are drawn with the
+// terminal's graphics protocol, and resources open in the resource detail
+// view.
+func (m *AppModel) openAttachment() tea.Cmd {
+	attachments := m.attachments()
+	if m.resultPane.selected >= len(attachments) {
+		return nil
+	}
+	a := attachments[m.resultPane.selected]
+
+	switch c := a.content.(type) {
+	case *mcp.ImageContent:
+		if a.img == nil {
+			m.logf("Cannot display %s; press s to save it", describeContent(c))
+			return nil
+		}
+		return showImage(a.img, c.Data, m.width, m.height)
+	case *mcp.AudioContent:
+		m.logf("Audio cannot be played here; press s to save it")
+		return nil
+	case *mcp.ResourceLink:
+		m.selectedResource = &mcp.Resource{
+			URI:         c.URI,
+			Name:        c.Name,
+			Title:       c.Title,
+			Description: c.Description,
+			MIMEType:    c.MIMEType,
+		}
+		m.selectedTemplate = nil
+		m.detailFromResult = true
+		m.state = resourceDetailView
+		return m.readResourceCmd()
+	case *mcp.EmbeddedResource:
+		if c.Resource == nil {
+			return nil
+		}
+		m.selectedResource = &mcp.Resource{URI: c.Resource.URI, Name: c.Resource.URI, MIMEType: c.Resource.MIMEType}
+		m.selectedTemplate = nil
+		m.detailFromResult = true
+		pretty, err := json.MarshalIndent(c.Resource, "", "  ")
+		if err != nil {
+			m.resourceResult = fmt.Sprintf("Error marshalling content: %v\n", err)
+		} else {
+			m.resourceResult = string(pretty)
+		}
+		m.resourceContents = []*mcp.ResourceContents{c.Resource}
+		m.resourceDiff = ""
+		m.state = resourceDetailView
+	}
+	return nil
+}
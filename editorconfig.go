@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// editorServer is a server entry in a Claude Desktop or VS Code MCP config
+// file. Both use the same field names.
+type editorServer struct {
+	Type    string            `json:"type"`
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	EnvFile string            `json:"envFile"`
+	Cwd     string            `json:"cwd"`
+}
+
+// loadEditorServers reads the servers from an editor's MCP config file:
+// mcpServers in claude_desktop_config.json, servers in .vscode/mcp.json, or
+// mcp.servers in a VS Code settings.json. Comments and trailing commas, which
+// VS Code allows, are ignored. Servers that mcp-cli cannot start are kept with
+// the reason, so that the others can still be used.
+func loadEditorServers(path string) (map[string]*serverConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		MCPServers map[string]*editorServer `json:"mcpServers"`
+		Servers    map[string]*editorServer `json:"servers"`
+		MCP        struct {
+			Servers map[string]*editorServer `json:"servers"`
+		} `json:"mcp"`
+	}
+	if err := json.Unmarshal(stripJSONComments(data), &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	entries := file.MCPServers
+	if entries == nil {
+		entries = file.Servers
+	}
+	if entries == nil {
+		entries = file.MCP.Servers
+	}
+
+	vars := newEditorVars(path)
+	servers := map[string]*serverConfig{}
+	for name, e := range entries {
+		if e == nil {
+			return nil, fmt.Errorf("%s: server %q is empty", path, name)
+		}
+		s, err := e.serverConfig(vars)
+		if err == nil {
+			err = s.check()
+		}
+		s.name = name
+		s.problem = err
+		servers[name] = s
+	}
+	return servers, nil
+}
+
+// serverConfig maps the entry onto a server as if it had been read from the
+// servers config file.
+func (e *editorServer) serverConfig(vars *editorVars) (*serverConfig, error) {
+	vars.err = nil
+	s := &serverConfig{
+		Transport: e.Type,
+		URL:       vars.expand(e.URL),
+		Env:       map[string]string{},
+		Headers:   map[string]string{},
+		EnvFile:   vars.expand(e.EnvFile),
+		Cwd:       vars.expand(e.Cwd),
+		imported:  true,
+	}
+	switch {
+	case s.Transport == "" && e.Command != "":
+		s.Transport = "stdio"
+	case s.Transport == "" && e.URL != "":
+		s.Transport = "http"
+	}
+	// The command is a single program path, which may contain spaces, so
+	// it goes in front of the arguments rather than being split into words.
+	if e.Command != "" {
+		s.Args = append(s.Args, vars.expand(e.Command))
+	}
+	for _, arg := range e.Args {
+		s.Args = append(s.Args, vars.expand(arg))
+	}
+	for k, v := range e.Env {
+		s.Env[k] = vars.expand(v)
+	}
+	for k, v := range e.Headers {
+		s.Headers[k] = vars.expand(v)
+	}
+	return s, vars.err
+}
+
+var editorVarRef = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// editorVars resolves the ${...} variables that VS Code allows in its MCP
+// config: ${workspaceFolder}, ${userHome} and ${env:NAME}. Inputs, which VS
+// Code prompts for, cannot be resolved.
+type editorVars struct {
+	workspace string
+	err       error
+}
+
+func newEditorVars(path string) *editorVars {
+	dir, _ := filepath.Abs(filepath.Dir(path))
+	if filepath.Base(dir) == ".vscode" {
+		dir = filepath.Dir(dir)
+	}
+	return &editorVars{workspace: dir}
+}
+
+func (v *editorVars) expand(s string) string {
+	return editorVarRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		switch {
+		case name == "workspaceFolder":
+			return v.workspace
+		case name == "userHome":
+			home, _ := os.UserHomeDir()
+			return home
+		case strings.HasPrefix(name, "env:"):
+			return os.Getenv(strings.TrimPrefix(name, "env:"))
+		case strings.HasPrefix(name, "input:") && v.err == nil:
+			v.err = fmt.Errorf("%s asks for input, which mcp-cli cannot prompt for", ref)
+		}
+		return ref
+	})
+}
+
+// stripJSONComments removes // and /* */ comments and trailing commas from
+// JSON, leaving strings untouched.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		switch c := data[i]; {
+		case c == '"':
+			// Copy the string, including escaped quotes.
+			j := i + 1
+			for j < len(data) && data[j] != '"' {
+				if data[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(data)-1)
+			out = append(out, data[i:j+1]...)
+			i = j
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			i--
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := strings.Index(string(data[i+2:]), "*/")
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case c == '}' || c == ']':
+			// Drop a comma before the closing bracket.
+			j := len(out) - 1
+			for j >= 0 && strings.IndexByte(" \t\r\n", out[j]) >= 0 {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStripJSONComments(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`{"a": 1} // comment`, `{"a": 1} `},
+		{"{\n  // comment\n  \"a\": 1\n}", "{\n  \n  \"a\": 1\n}"},
+		{`/* block */{"a": 1}`, `{"a": 1}`},
+		{`{"a": /* inline */ 1}`, `{"a":  1}`},
+		{`{"a": 1} /* unterminated`, `{"a": 1} `},
+		{`{"url": "http://example.com/*x*/"}`, `{"url": "http://example.com/*x*/"}`},
+		{`{"a": "say \"// not a comment\""}`, `{"a": "say \"// not a comment\""}`},
+		{`{"a": "back\\"} // comment`, `{"a": "back\\"} `},
+		{`{"a": ",]", "b": ",}"}`, `{"a": ",]", "b": ",}"}`},
+		{`{"a": [1, 2,],}`, `{"a": [1, 2]}`},
+		{"{\n  \"a\": 1,\n}", "{\n  \"a\": 1\n}"},
+		{`{"a": 1, /* last */ }`, `{"a": 1  }`},
+	}
+	for _, tt := range tests {
+		got := string(stripJSONComments([]byte(tt.in)))
+		if got != tt.want {
+			t.Errorf("stripJSONComments(%q) = %q, want %q", tt.in, got, tt.want)
+			continue
+		}
+		if !json.Valid([]byte(got)) {
+			t.Errorf("stripJSONComments(%q) = %q, which is not valid JSON", tt.in, got)
+		}
+	}
+}
+
+func TestEditorVarsExpand(t *testing.T) {
+	t.Setenv("HOME", "/home/u")
+	t.Setenv("MCP_CLI_TEST_VAR", "value")
+	tests := []struct {
+		in, want string
+		err      bool
+	}{
+		{"${workspaceFolder}/server.js", "/ws/server.js", false},
+		{"${userHome}/bin", "/home/u/bin", false},
+		{"--token=${env:MCP_CLI_TEST_VAR}", "--token=value", false},
+		{"${env:MCP_CLI_UNSET_VAR}", "", false},
+		{"${unknown} $HOME", "${unknown} $HOME", false},
+		{"Bearer ${input:token}", "Bearer ${input:token}", true},
+	}
+	for _, tt := range tests {
+		v := &editorVars{workspace: "/ws"}
+		got := v.expand(tt.in)
+		if got != tt.want {
+			t.Errorf("expand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if (v.err != nil) != tt.err {
+			t.Errorf("expand(%q) error = %v, want error %v", tt.in, v.err, tt.err)
+		}
+	}
+
+	if got := newEditorVars("/proj/.vscode/mcp.json").workspace; got != "/proj" {
+		t.Errorf("workspace of .vscode/mcp.json = %q, want /proj", got)
+	}
+	if got := newEditorVars("/proj/claude_desktop_config.json").workspace; got != "/proj" {
+		t.Errorf("workspace of claude_desktop_config.json = %q, want /proj", got)
+	}
+}
+
+func TestLoadEditorServers(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".vscode")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "mcp.json")
+	config := `{
+  // Servers for this workspace.
+  "servers": {
+    "files": {
+      "command": "/opt/my server/bin",
+      "args": ["--root", "${workspaceFolder}", "http://x//y"],
+    },
+    "remote": {"url": "https://example.com/mcp", "headers": {"Authorization": "Bearer ${input:token}"}},
+  },
+}`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	servers, err := loadEditorServers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := servers["files"]
+	if files == nil || files.problem != nil {
+		t.Fatalf("files = %+v", files)
+	}
+	wantArgs := []string{"/opt/my server/bin", "--root", filepath.Dir(dir), "http://x//y"}
+	if files.kind() != "stdio" || !slices.Equal(files.Args, wantArgs) {
+		t.Errorf("files = %s %q, want stdio %q", files.kind(), files.Args, wantArgs)
+	}
+	remote := servers["remote"]
+	if remote == nil || remote.problem == nil || !strings.Contains(remote.problem.Error(), "${input:token}") {
+		t.Errorf("remote = %+v, want a problem naming ${input:token}", remote)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// elicitationRequest is sent when the server calls elicitation/create. The
+// handler waits for the operator's answer on reply.
+type elicitationRequest struct {
+	params *mcp.ElicitParams
+	reply  chan *mcp.ElicitResult
+}
+
+// elicitationAbandoned is sent when the server stops waiting for an
+// elicitation request.
+type elicitationAbandoned struct {
+	reply chan *mcp.ElicitResult
+}
+
+// handleElicit shows an elicitation request as a form and waits for the
+// operator to accept, decline or cancel it.
+func handleElicit(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+	reply := make(chan *mcp.ElicitResult, 1)
+	if !sendToProgram(elicitationRequest{params: req.Params, reply: reply}) {
+		return nil, errors.New("elicitation is only supported in the interactive TUI")
+	}
+	select {
+	case result := <-reply:
+		return result, nil
+	case <-ctx.Done():
+		sendToProgram(elicitationAbandoned{reply: reply})
+		return nil, ctx.Err()
+	}
+}
+
+// queueElicitation adds an elicitation request and opens its form if no
+// other request is being shown.
+func (m *AppModel) queueElicitation(req elicitationRequest) tea.Cmd {
+	m.elicitQueue = append(m.elicitQueue, req)
+	m.logf("========\nServer requested input: %s", req.params.Message)
+	if len(m.elicitQueue) > 1 {
+		return nil
+	}
+	m.elicitReturnState = m.state
+	m.state = elicitationView
+	m.resetElicitationForm()
+	return nil
+}
+
+// dropElicitation removes the request answered through reply from the queue
+// and moves on to the next one, or back to the previous view.
+func (m *AppModel) dropElicitation(reply chan *mcp.ElicitResult) {
+	for i, req := range m.elicitQueue {
+		if req.reply != reply {
+			continue
+		}
+		m.elicitQueue = append(m.elicitQueue[:i], m.elicitQueue[i+1:]...)
+		if i != 0 {
+			return
+		}
+		if len(m.elicitQueue) > 0 {
+			m.resetElicitationForm()
+		} else if m.state == elicitationView {
+			m.state = m.elicitReturnState
+		}
+		return
+	}
+}
+
+func (m *AppModel) resetElicitationForm() {
+	m.elicitForm = newArgForm(m.elicitQueue[0].params.RequestedSchema)
+}
+
+// answerElicitation sends result for the request being shown.
+func (m *AppModel) answerElicitation(result *mcp.ElicitResult) {
+	req := m.elicitQueue[0]
+	m.logf("Elicitation answered: %s", result.Action)
+	req.reply <- result
+	m.dropElicitation(req.reply)
+}
+
+func (m *AppModel) updateElicitationView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if len(m.elicitQueue) == 0 {
+		m.state = m.elicitReturnState
+		return m, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "ctrl+d":
+		m.answerElicitation(&mcp.ElicitResult{Action: "decline"})
+		return m, nil
+	case "esc":
+		m.answerElicitation(&mcp.ElicitResult{Action: "cancel"})
+		return m, nil
+	case "enter":
+		if !m.elicitForm.AtLast() {
+			m.elicitForm.Next()
+			return m, nil
+		}
+		return m.acceptElicitation()
+	}
+
+	return m, m.elicitForm.Update(msg)
+}
+
+// acceptElicitation checks the form and sends its content to the server.
+func (m *AppModel) acceptElicitation() (tea.Model, tea.Cmd) {
+	if !m.elicitForm.Validate(m.elicitQueue[0].params.RequestedSchema) {
+		return m, nil
+	}
+	content, _ := m.elicitForm.Value()
+	m.answerElicitation(&mcp.ElicitResult{Action: "accept", Content: content})
+	return m, nil
+}
+
+// elicitationFormView renders the form for the elicitation request being shown.
+func (m *AppModel) elicitationFormView() string {
+	if len(m.elicitQueue) == 0 {
+		return ""
+	}
+	params := m.elicitQueue[0].params
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Input requested by the server (1 of %d):\n\n", len(m.elicitQueue)))
+	b.WriteString(params.Message)
+	b.WriteString("\n\n")
+	b.WriteString(m.elicitForm.View())
+	b.WriteString("\nPress Enter to accept, Ctrl+D to decline, Esc to cancel.")
+	return b.String()
+}
@@ -0,0 +1,558 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+var (
+	formFocusStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")) // Pink
+	formHintStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))            // Gray
+)
+
+// argForm is an input form generated from a JSON schema. Each property gets a
+// field suited to its type, and objects and arrays hold nested fields.
+type argForm struct {
+	fields []*formField
+	focus  int            // index into rows()
+	err    string         // error for the form as a whole
+	extra  map[string]any // keys set with SetValue that have no field
+}
+
+type fieldKind int
+
+const (
+	textField fieldKind = iota
+	enumField
+	boolField
+	arrayField
+	objectField
+)
+
+type formField struct {
+	name     string
+	schema   *jsonschema.Schema
+	required bool
+	isItem   bool // an item of an array field
+	kind     fieldKind
+	err      string
+
+	input    textinput.Model // textField
+	choice   int             // enumField: index into schema.Enum, or -1 when unset
+	boolVal  *bool           // boolField: nil when unset
+	children []*formField    // arrayField items and objectField properties
+}
+
+// formRow is a field as laid out in the form, with its position in the tree.
+type formRow struct {
+	field  *formField
+	parent *formField
+	depth  int
+	path   string
+}
+
+// newArgForm builds a form for the properties of schema.
+func newArgForm(schema *jsonschema.Schema) *argForm {
+	f := &argForm{fields: newFormFields(schema)}
+	f.focus = f.nextFocusable(-1, 1)
+	f.refocus()
+	return f
+}
+
+// newFormFields returns a field for each property of schema, with required
+// properties first and each group sorted by name.
+func newFormFields(schema *jsonschema.Schema) []*formField {
+	if schema == nil {
+		return nil
+	}
+	var names []string
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ri, rj := slices.Contains(schema.Required, names[i]), slices.Contains(schema.Required, names[j])
+		if ri != rj {
+			return ri
+		}
+		return names[i] < names[j]
+	})
+
+	var fields []*formField
+	for _, name := range names {
+		fields = append(fields, newFormField(name, schema.Properties[name], slices.Contains(schema.Required, name)))
+	}
+	return fields
+}
+
+func newFormField(name string, schema *jsonschema.Schema, required bool) *formField {
+	if schema == nil {
+		schema = &jsonschema.Schema{}
+	}
+	f := &formField{name: name, schema: schema, required: required, choice: -1}
+	typ := schemaType(schema)
+	switch {
+	case len(schema.Enum) > 0:
+		f.kind = enumField
+		if required {
+			f.choice = 0
+		}
+	case typ == "boolean":
+		f.kind = boolField
+		if required {
+			f.boolVal = new(bool)
+		}
+	case typ == "array" && schema.Items != nil:
+		f.kind = arrayField
+	case typ == "object" && len(schema.Properties) > 0:
+		f.kind = objectField
+		f.children = newFormFields(schema)
+	default:
+		f.kind = textField
+		f.input = textinput.New()
+		f.input.Placeholder = schema.Description
+		f.input.CharLimit = 256
+		f.input.Width = 50
+	}
+
+	if len(schema.Default) > 0 {
+		var v any
+		if err := json.Unmarshal(schema.Default, &v); err == nil {
+			f.setValue(v)
+		}
+	}
+	return f
+}
+
+// schemaType returns the JSON type of schema, ignoring "null" when the schema
+// lists several types.
+func schemaType(schema *jsonschema.Schema) string {
+	if schema == nil {
+		return ""
+	}
+	if schema.Type != "" {
+		return schema.Type
+	}
+	for _, t := range schema.Types {
+		if t != "null" {
+			return t
+		}
+	}
+	return ""
+}
+
+// newItem returns a new, empty item for an array field.
+func (f *formField) newItem() *formField {
+	item := newFormField("", f.schema.Items, true)
+	item.isItem = true
+	return item
+}
+
+// setValue fills the field, and any nested fields, from a JSON value. A nil
+// value clears the field.
+func (f *formField) setValue(v any) {
+	switch f.kind {
+	case textField:
+		switch s := v.(type) {
+		case nil:
+			f.input.SetValue("")
+		case string:
+			f.input.SetValue(s)
+		default:
+			data, _ := json.Marshal(s)
+			f.input.SetValue(string(data))
+		}
+	case enumField:
+		f.choice = -1
+		if f.required {
+			f.choice = 0
+		}
+		want, _ := json.Marshal(v)
+		for i, option := range f.schema.Enum {
+			if got, _ := json.Marshal(option); string(got) == string(want) {
+				f.choice = i
+			}
+		}
+	case boolField:
+		f.boolVal = nil
+		if b, ok := v.(bool); ok {
+			f.boolVal = &b
+		} else if f.required {
+			f.boolVal = new(bool)
+		}
+	case arrayField:
+		f.children = nil
+		items, _ := v.([]any)
+		for _, item := range items {
+			child := f.newItem()
+			child.setValue(item)
+			f.children = append(f.children, child)
+		}
+	case objectField:
+		props, _ := v.(map[string]any)
+		for _, child := range f.children {
+			child.setValue(props[child.name])
+		}
+	}
+}
+
+// value returns the JSON value of the field, and false when it is unset.
+func (f *formField) value(path string) (any, bool, error) {
+	switch f.kind {
+	case textField:
+		s := f.input.Value()
+		if s == "" {
+			return nil, false, nil
+		}
+		v, err := textValue(f.schema, s)
+		if err != nil {
+			return s, true, fmt.Errorf("invalid value for arg '%s' (%s): %w", path, schemaType(f.schema), err)
+		}
+		return v, true, nil
+	case enumField:
+		if f.choice < 0 {
+			return nil, false, nil
+		}
+		return f.schema.Enum[f.choice], true, nil
+	case boolField:
+		if f.boolVal == nil {
+			return nil, false, nil
+		}
+		return *f.boolVal, true, nil
+	case arrayField:
+		if len(f.children) == 0 {
+			return nil, false, nil
+		}
+		items := []any{}
+		var errs []error
+		for i, child := range f.children {
+			v, ok, err := child.value(fmt.Sprintf("%s[%d]", path, i))
+			errs = append(errs, err)
+			if ok {
+				items = append(items, v)
+			}
+		}
+		return items, true, errors.Join(errs...)
+	case objectField:
+		obj, err := fieldsValue(f.children, path+".")
+		return obj, len(obj) > 0, err
+	}
+	return nil, false, nil
+}
+
+// textValue converts text typed into a field to the type declared by schema.
+// Text for fields without a simple type is parsed as JSON when possible.
+func textValue(schema *jsonschema.Schema, s string) (any, error) {
+	switch schemaType(schema) {
+	case "string":
+		return s, nil
+	case "number", "integer", "boolean":
+		return coerceArg(schema, s)
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s, nil
+	}
+	return v, nil
+}
+
+func fieldsValue(fields []*formField, prefix string) (map[string]any, error) {
+	obj := make(map[string]any)
+	var errs []error
+	for _, f := range fields {
+		v, ok, err := f.value(prefix + f.name)
+		errs = append(errs, err)
+		if ok {
+			obj[f.name] = v
+		}
+	}
+	return obj, errors.Join(errs...)
+}
+
+// Value returns the arguments entered in the form. Text that cannot be
+// converted to its declared type is kept as a string and reported in the error.
+func (f *argForm) Value() (map[string]any, error) {
+	obj, err := fieldsValue(f.fields, "")
+	for k, v := range f.extra {
+		if _, ok := obj[k]; !ok {
+			obj[k] = v
+		}
+	}
+	return obj, err
+}
+
+// SetValue fills the form from args. Keys that have no field are kept and
+// passed through by Value.
+func (f *argForm) SetValue(args map[string]any) {
+	f.err = ""
+	f.extra = nil
+	for k, v := range args {
+		if !slices.ContainsFunc(f.fields, func(field *formField) bool { return field.name == k }) {
+			if f.extra == nil {
+				f.extra = make(map[string]any)
+			}
+			f.extra[k] = v
+		}
+	}
+	for _, field := range f.fields {
+		field.clearErrors()
+		field.setValue(args[field.name])
+	}
+	if rows := f.rows(); f.focus >= len(rows) {
+		f.focus = f.nextFocusable(len(rows), -1)
+	}
+	f.refocus()
+}
+
+// rows lays out the fields of the form in display order.
+func (f *argForm) rows() []formRow {
+	var rows []formRow
+	var walk func(fields []*formField, parent *formField, depth int, prefix string)
+	walk = func(fields []*formField, parent *formField, depth int, prefix string) {
+		for i, field := range fields {
+			path := prefix + field.name
+			if parent != nil && parent.kind == arrayField {
+				path = fmt.Sprintf("%s[%d]", strings.TrimSuffix(prefix, "."), i)
+			}
+			rows = append(rows, formRow{field: field, parent: parent, depth: depth, path: path})
+			walk(field.children, field, depth+1, path+".")
+		}
+	}
+	walk(f.fields, nil, 0, "")
+	return rows
+}
+
+// enclosingArray returns the nearest array field that row is inside of, and
+// the item of that array holding row, which is an object for arrays of
+// objects. It returns nil if row is not inside an array.
+func (f *argForm) enclosingArray(row formRow) (array, item *formField) {
+	rows := f.rows()
+	item, parent := row.field, row.parent
+	for parent != nil && parent.kind != arrayField {
+		i := slices.IndexFunc(rows, func(r formRow) bool { return r.field == parent })
+		item, parent = parent, rows[i].parent
+	}
+	return parent, item
+}
+
+// nextFocusable returns the index of the next row from i in direction dir
+// that can take focus, or i if there is none.
+func (f *argForm) nextFocusable(i, dir int) int {
+	rows := f.rows()
+	for j := i + dir; j >= 0 && j < len(rows); j += dir {
+		if rows[j].field.kind != objectField {
+			return j
+		}
+	}
+	return i
+}
+
+// refocus focuses the text input of the focused row and blurs all others.
+func (f *argForm) refocus() {
+	for i, row := range f.rows() {
+		if row.field.kind != textField {
+			continue
+		}
+		if i == f.focus {
+			row.field.input.Focus()
+		} else {
+			row.field.input.Blur()
+		}
+	}
+}
+
+// AtLast reports whether the last focusable field has focus.
+func (f *argForm) AtLast() bool {
+	return f.nextFocusable(f.focus, 1) == f.focus
+}
+
+// Next moves the focus to the next field.
+func (f *argForm) Next() {
+	f.focus = f.nextFocusable(f.focus, 1)
+	f.refocus()
+}
+
+// Update handles a key for the focused field: Up and Down move between
+// fields, Left, Right and Space change enums and checkboxes, and Ctrl+N and
+// Ctrl+D add and remove array items.
+func (f *argForm) Update(msg tea.Msg) tea.Cmd {
+	rows := f.rows()
+	if f.focus < 0 || f.focus >= len(rows) {
+		return nil
+	}
+	row := rows[f.focus]
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg.String() != "up" && keyMsg.String() != "down" {
+			row.field.err = ""
+		}
+		switch keyMsg.String() {
+		case "up":
+			f.focus = f.nextFocusable(f.focus, -1)
+			f.refocus()
+			return nil
+		case "down":
+			f.Next()
+			return nil
+		case "ctrl+n":
+			array := row.field
+			if array.kind != arrayField {
+				array, _ = f.enclosingArray(row)
+			}
+			if array != nil {
+				array.children = append(array.children, array.newItem())
+				for i, r := range f.rows() {
+					if r.field == array.children[len(array.children)-1] {
+						f.focus = i
+					}
+				}
+				f.focus = f.nextFocusable(f.focus-1, 1)
+				f.refocus()
+			}
+			return nil
+		case "ctrl+d":
+			if array, item := f.enclosingArray(row); array != nil {
+				// Keep the focus where the removed item started, or move it
+				// back into the array if that was the last item.
+				f.focus = slices.IndexFunc(f.rows(), func(r formRow) bool { return r.field == item })
+				i := slices.Index(array.children, item)
+				array.children = slices.Delete(array.children, i, i+1)
+				if f.focus >= len(f.rows()) || i == len(array.children) || f.rows()[f.focus].field.kind == objectField {
+					f.focus = f.nextFocusable(f.focus, -1)
+				}
+				f.refocus()
+			}
+			return nil
+		}
+
+		switch row.field.kind {
+		case enumField:
+			switch keyMsg.String() {
+			case "left":
+				row.field.cycleChoice(-1)
+			case "right", " ":
+				row.field.cycleChoice(1)
+			}
+			return nil
+		case boolField:
+			if keyMsg.String() == " " || keyMsg.String() == "left" || keyMsg.String() == "right" {
+				row.field.toggle()
+			}
+			return nil
+		}
+	}
+
+	if row.field.kind != textField {
+		return nil
+	}
+	var cmd tea.Cmd
+	row.field.input, cmd = row.field.input.Update(msg)
+	return cmd
+}
+
+// cycleChoice moves to the next or previous enum value. Optional enums can
+// also be left unset.
+func (f *formField) cycleChoice(dir int) {
+	first := -1
+	if f.required {
+		first = 0
+	}
+	n := len(f.schema.Enum) - first
+	f.choice = (f.choice-first+dir+n)%n + first
+}
+
+// toggle cycles a checkbox through true and false, and unset when optional.
+func (f *formField) toggle() {
+	switch {
+	case f.boolVal == nil:
+		f.boolVal = new(bool)
+		*f.boolVal = true
+	case *f.boolVal:
+		*f.boolVal = false
+	case f.required:
+		*f.boolVal = true
+	default:
+		f.boolVal = nil
+	}
+}
+
+// View renders the form.
+func (f *argForm) View() string {
+	var b strings.Builder
+	if f.err != "" {
+		b.WriteString(fieldErrorStyle.Render(f.err) + "\n\n")
+	}
+	for i, row := range f.rows() {
+		field := row.field
+		indent := strings.Repeat("  ", row.depth)
+		focused := i == f.focus
+
+		label := row.path
+		if field.required && !field.isItem {
+			label += "*"
+		}
+		if field.schema.Title != "" {
+			label += " - " + field.schema.Title
+		}
+		if typ := schemaType(field.schema); typ != "" && typ != "string" && field.kind != objectField {
+			label += fmt.Sprintf(" (%s)", typ)
+		}
+		if field.kind == arrayField && focused {
+			label += formHintStyle.Render(" Ctrl+N to add an item")
+		}
+		if focused && row.parent != nil && row.parent.kind == arrayField {
+			label += formHintStyle.Render(" Ctrl+D to remove")
+		}
+		b.WriteString(indent + label + "\n")
+
+		var widget string
+		switch field.kind {
+		case textField:
+			widget = field.input.View()
+		case enumField:
+			value := "(not set)"
+			if field.choice >= 0 {
+				value = fmt.Sprint(field.schema.Enum[field.choice])
+			}
+			widget = fmt.Sprintf("< %s >", value)
+		case boolField:
+			switch {
+			case field.boolVal == nil:
+				widget = "[-] (not set)"
+			case *field.boolVal:
+				widget = "[x] true"
+			default:
+				widget = "[ ] false"
+			}
+		case arrayField:
+			if len(field.children) == 0 {
+				widget = formHintStyle.Render("(no items)")
+			}
+		}
+		if focused && (field.kind == enumField || field.kind == boolField) {
+			widget = formFocusStyle.Render(widget)
+		}
+		if widget != "" {
+			b.WriteString(indent + widget + "\n")
+		}
+		if field.err != "" {
+			b.WriteString(indent + fieldErrorStyle.Render(field.err) + "\n")
+		}
+		if field.kind != objectField && (field.kind != arrayField || len(field.children) == 0) {
+			b.WriteString("\n")
+		}
+	}
+	if len(f.extra) > 0 {
+		data, _ := json.Marshal(f.extra)
+		b.WriteString(formHintStyle.Render("Arguments not in the schema: "+string(data)) + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// historyLimit is the number of calls kept in the history of each server.
+const historyLimit = 500
+
+var noHistory bool
+
+// historyEntry is a tool call recorded in the call history.
+type historyEntry struct {
+	Server    string              `json:"server"`
+	Tool      string              `json:"tool"`
+	Arguments map[string]any      `json:"arguments"`
+	Started   time.Time           `json:"started"`
+	Duration  time.Duration       `json:"duration"`
+	Result    *mcp.CallToolResult `json:"result,omitempty"`
+	Error     string              `json:"error,omitempty"`
+}
+
+// callHistory is the call history of one server. It is stored as one JSON
+// object per line in the user's config directory, in a file named after a
+// hash of the server's identity. Arguments and results can hold secrets, so
+// only the user can read the file.
+//
+// Entries are added by the TUI and saved by commands running in the
+// background, so that a large rewrite does not hold up the UI.
+type callHistory struct {
+	server string
+	path   string
+
+	mu      sync.Mutex      // guards changes to entries, which saves read
+	entries []*historyEntry // oldest first
+
+	fileMu sync.Mutex // serializes writes to the file
+	lines  int        // lines in the file
+}
+
+// historySaved is sent when a call has been saved to the history file.
+type historySaved struct {
+	err error
+}
+
+// serverID identifies a server for the call history by its transport and
+// its command or URL.
+func serverID(kind, target string) string {
+	return kind + " " + target
+}
+
+// openHistory loads the call history of server. It returns nil if history
+// is turned off with --no-history.
+func openHistory(server string) (*callHistory, error) {
+	if noHistory {
+		return nil, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(server))
+	h := &callHistory{
+		server: server,
+		path:   filepath.Join(dir, "mcp-cli", "history", hex.EncodeToString(sum[:8])+".jsonl"),
+	}
+
+	f, err := os.Open(h.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Results can be large, so read whole lines rather than using a Scanner.
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			h.lines++
+			var e historyEntry
+			// Skip lines that were cut short, for example by a crash.
+			if json.Unmarshal(line, &e) == nil {
+				h.entries = append(h.entries, &e)
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(h.entries) > historyLimit {
+		h.entries = h.entries[len(h.entries)-historyLimit:]
+	}
+	return h, nil
+}
+
+// add records e and returns a command that saves it to the history file.
+func (h *callHistory) add(e *historyEntry) tea.Cmd {
+	e.Server = h.server
+	h.mu.Lock()
+	h.entries = append(h.entries, e)
+	if len(h.entries) > historyLimit {
+		h.entries = h.entries[len(h.entries)-historyLimit:]
+	}
+	h.mu.Unlock()
+	return func() tea.Msg {
+		return historySaved{err: h.save(e)}
+	}
+}
+
+// save appends e to the history file. Once the file holds twice historyLimit
+// calls, it is rewritten with the latest historyLimit calls instead, so that
+// it is only rewritten every historyLimit calls rather than on every call.
+func (h *callHistory) save(e *historyEntry) error {
+	h.fileMu.Lock()
+	defer h.fileMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+
+	if h.lines >= 2*historyLimit {
+		h.mu.Lock()
+		entries := slices.Clone(h.entries)
+		h.mu.Unlock()
+		if err := h.rewrite(entries); err != nil {
+			return err
+		}
+		h.lines = len(entries)
+		return nil
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(e); err != nil {
+		return err
+	}
+	h.lines++
+	return nil
+}
+
+func (h *callHistory) rewrite(entries []*historyEntry) error {
+	tmp := h.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
+
+type historyItem struct {
+	entry *historyEntry
+}
+
+func (i historyItem) Title() string {
+	return fmt.Sprintf("%s  %s", i.entry.Tool, i.entry.Started.Local().Format("2006-01-02 15:04:05"))
+}
+
+func (i historyItem) Description() string {
+	status := "ok"
+	switch {
+	case i.entry.Error != "":
+		status = i.entry.Error
+	case i.entry.Result != nil && i.entry.Result.IsError:
+		status = "tool error"
+	}
+	args, _ := json.Marshal(i.entry.Arguments)
+	return fmt.Sprintf("%s in %s  %s", status, i.entry.Duration.Round(time.Millisecond), args)
+}
+
+func (i historyItem) FilterValue() string { return i.entry.Tool }
+
+func newHistoryList(h *callHistory) list.Model {
+	l := list.New(historyItems(h), list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Call history"
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "show result")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "re-run")),
+			key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit arguments")),
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tools")),
+		}
+	}
+	return l
+}
+
+// historyItems returns the calls in h, newest first.
+func historyItems(h *callHistory) []list.Item {
+	items := []list.Item{}
+	if h == nil {
+		return items
+	}
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		items = append(items, historyItem{entry: h.entries[i]})
+	}
+	return items
+}
+
+// recordCall adds a finished call to the history. It is saved to the file in
+// the background.
+func (m *AppModel) recordCall(e *historyEntry) tea.Cmd {
+	if e == nil || m.history == nil {
+		return nil
+	}
+	saveCmd := m.history.add(e)
+	return tea.Batch(saveCmd, m.historyList.SetItems(historyItems(m.history)))
+}
+
+func (m *AppModel) updateHistoryView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.historyList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.historyList, cmd = m.historyList.Update(msg)
+		return m, cmd
+	}
+
+	selected, _ := m.historyList.SelectedItem().(historyItem)
+	switch keyMsg.String() {
+	case "t":
+		m.state = toolSelectionView
+		return m, nil
+	case "enter":
+		if selected.entry != nil {
+			m.showHistoryEntry(selected.entry)
+		}
+		return m, nil
+	case "r":
+		if selected.entry != nil && m.useHistoryEntry(selected.entry) {
+			return m.callTool(selected.entry.Arguments)
+		}
+		return m, nil
+	case "e":
+		if selected.entry == nil || !m.useHistoryEntry(selected.entry) {
+			return m, nil
+		}
+		if m.argForm == nil {
+			m.logf("%s takes no arguments", selected.entry.Tool)
+			return m, nil
+		}
+		m.state = argumentInputView
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.historyList, cmd = m.historyList.Update(msg)
+	return m, cmd
+}
+
+// useHistoryEntry selects the tool of a recorded call and fills the argument
+// form with its arguments. It reports false if the server no longer offers
+// the tool.
+func (m *AppModel) useHistoryEntry(e *historyEntry) bool {
+	var tool *mcp.Tool
+	for _, t := range m.tools {
+		if t.Name == e.Tool {
+			tool = t
+			break
+		}
+	}
+	if tool == nil {
+		m.logf("Tool '%s' is no longer offered by the server", e.Tool)
+		return false
+	}
+
+	m.selectedTool = tool
+	m.argForm = nil
+	m.argRawMode = false
+	if tool.InputSchema != nil && len(tool.InputSchema.Properties) > 0 {
+		m.argForm = newArgForm(tool.InputSchema)
+		m.argForm.SetValue(e.Arguments)
+	}
+	return true
+}
+
+// showHistoryEntry shows the result of a recorded call in the result view.
+func (m *AppModel) showHistoryEntry(e *historyEntry) {
+	var tool *mcp.Tool
+	if m.useHistoryEntry(e) {
+		tool = m.selectedTool
+	}
+	m.resultTool = e.Tool
+	m.resultArgs = e.Arguments
+	m.resultStructured = structuredResult{}
+	m.resultPane.reset()
+	if e.Result == nil {
+		m.resultBlocks = []resultBlock{{text: "Call failed: " + e.Error}}
+	} else {
+		m.resultBlocks = resultBlocks(e.Result)
+		m.resultStructured = checkStructuredContent(tool, e.Result)
+	}
+	m.state = toolResultView
+}
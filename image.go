@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// imageProtocol selects how images are drawn: "auto", "kitty", "iterm",
+// "sixel" or "halfblocks". The renderer redraws the view as text, so inline
+// previews can only use the kitty protocol, whose images are placed with
+// placeholder characters. iTerm2 and sixel images are only drawn while the
+// TUI is suspended, and their previews use half blocks.
+var imageProtocol = "auto"
+
+// imageProtocols are the values accepted by --image-protocol.
+var imageProtocols = []string{"auto", "kitty", "iterm", "sixel", "halfblocks"}
+
+// imageProtocolFlag is the --image-protocol flag. It rejects unknown values
+// when the flags are parsed, rather than falling back to half blocks.
+type imageProtocolFlag struct{}
+
+func (imageProtocolFlag) String() string { return imageProtocol }
+func (imageProtocolFlag) Type() string   { return "string" }
+
+func (imageProtocolFlag) Set(v string) error {
+	if !slices.Contains(imageProtocols, v) {
+		return fmt.Errorf("must be one of %s", strings.Join(imageProtocols, ", "))
+	}
+	imageProtocol = v
+	return nil
+}
+
+// detectImageProtocol guesses the graphics protocol of the terminal from the
+// environment.
+func detectImageProtocol() string {
+	if imageProtocol != "auto" {
+		return imageProtocol
+	}
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || program == "ghostty":
+		return "kitty"
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return "iterm"
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm"):
+		return "sixel"
+	}
+	return "halfblocks"
+}
+
+// fitCells returns the size in terminal cells of an image scaled down to fit
+// in cols by rows, assuming cells are twice as high as they are wide.
+func fitCells(bounds image.Rectangle, cols, rows int) (int, int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= 0 || h <= 0 || cols <= 0 || rows <= 0 {
+		return 0, 0
+	}
+	c := min(w, cols)
+	r := (h*c/w + 1) / 2
+	if r > rows {
+		r = rows
+		c = max(w*r*2/h, 1)
+	}
+	return c, max(r, 1)
+}
+
+// halfBlocks draws img in cols by rows cells. Each cell shows two pixels, the
+// upper half block taking the top pixel's colour as foreground and the bottom
+// pixel's colour as background.
+func halfBlocks(img image.Image, cols, rows int) string {
+	b := img.Bounds()
+	pixel := func(x, y int) (lipgloss.Color, bool) {
+		px := b.Min.X + x*b.Dx()/cols
+		py := b.Min.Y + y*b.Dy()/(rows*2)
+		r, g, bl, a := img.At(px, py).RGBA()
+		if a < 0x8000 {
+			return "", false
+		}
+		return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, bl>>8)), true
+	}
+
+	var out strings.Builder
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			out.WriteString("\n")
+		}
+		for x := 0; x < cols; x++ {
+			top, topOK := pixel(x, y*2)
+			bottom, bottomOK := pixel(x, y*2+1)
+			switch {
+			case topOK && bottomOK:
+				out.WriteString(lipgloss.NewStyle().Foreground(top).Background(bottom).Render("▀"))
+			case topOK:
+				out.WriteString(lipgloss.NewStyle().Foreground(top).Render("▀"))
+			case bottomOK:
+				out.WriteString(lipgloss.NewStyle().Foreground(bottom).Render("▄"))
+			default:
+				out.WriteString(" ")
+			}
+		}
+	}
+	return out.String()
+}
+
+// kittyImage encodes img for the kitty graphics protocol, scaled to cols by
+// rows cells.
+func kittyImage(img image.Image, id uint32, cols, rows int) string {
+	return kittyTransmit(img, fmt.Sprintf("a=T,f=100,i=%d,c=%d,r=%d,q=2", id, cols, rows))
+}
+
+// kittyTransmit encodes img as PNG for the kitty graphics protocol, with the
+// given control keys. The data is sent in chunks of at most 4096 bytes.
+func kittyTransmit(img image.Image, keys string) string {
+	var buf bytes.Buffer
+	png.Encode(&buf, img)
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var out strings.Builder
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(len(data), 4096)]
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&out, "\x1b_G%s,m=%d;%s\x1b\\", keys, more, chunk)
+		} else {
+			fmt.Fprintf(&out, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return out.String()
+}
+
+// kittyPlaceholder is the character that kitty replaces with a cell of an
+// image placed virtually, with U=1. Diacritics after it give the row and the
+// column of the cell, and its foreground colour gives the image id.
+const kittyPlaceholder = '\U0010EEEE'
+
+// kittyDiacritics are the combining characters that number the rows and
+// columns of placeholder cells, from kitty's rowcolumn-diacritics.txt. Only as
+// many as previews need are listed.
+var kittyDiacritics = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F, 0x0346, 0x034A,
+	0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357, 0x035B, 0x0363, 0x0364, 0x0365,
+	0x0366, 0x0367, 0x0368, 0x0369, 0x036A, 0x036B, 0x036C, 0x036D, 0x036E, 0x036F,
+	0x0483, 0x0484, 0x0485, 0x0486, 0x0487, 0x0592, 0x0593, 0x0594, 0x0595, 0x0597,
+	0x0598, 0x0599, 0x059C, 0x059D, 0x059E, 0x059F, 0x05A0, 0x05A1, 0x05A8, 0x05A9,
+	0x05AB, 0x05AC, 0x05AF, 0x05C4, 0x0610, 0x0611, 0x0612, 0x0613, 0x0614, 0x0615,
+}
+
+// newKittyImageID returns an id for an image sent to kitty. Ids are random
+// so that they are unlikely to replace images of other programs. Placeholders
+// carry the id as a 24-bit colour, so it must fit in 24 bits.
+func newKittyImageID() uint32 {
+	return rand.Uint32N(1<<24-1) + 1
+}
+
+// kittyPlaceholders returns the text that kitty draws as the image with the
+// given id, placed virtually in cols by rows cells.
+func kittyPlaceholders(id uint32, cols, rows int) string {
+	var out strings.Builder
+	for y := 0; y < rows; y++ {
+		if y > 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "\x1b[38;2;%d;%d;%dm", id>>16&0xff, id>>8&0xff, id&0xff)
+		for x := 0; x < cols; x++ {
+			out.WriteRune(kittyPlaceholder)
+			out.WriteRune(kittyDiacritics[y])
+			out.WriteRune(kittyDiacritics[x])
+		}
+		out.WriteString("\x1b[39m")
+	}
+	return out.String()
+}
+
+// kittyPreview sends img to the terminal, placed virtually in cols by rows
+// cells, and returns the placeholders that show it. The image is written
+// straight to the terminal because the renderer only writes text; sending it
+// again with the same id replaces the earlier placement.
+func kittyPreview(img image.Image, id uint32, cols, rows int) string {
+	os.Stdout.WriteString(kittyTransmit(img, fmt.Sprintf("a=T,U=1,f=100,i=%d,c=%d,r=%d,q=2", id, cols, rows)))
+	return kittyPlaceholders(id, cols, rows)
+}
+
+// itermImage encodes the image file data for the iTerm2 inline image
+// protocol, scaled to cols by rows cells.
+func itermImage(data []byte, cols, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+}
+
+// sixelImage encodes img as sixels, scaled to width pixels wide. Colours are
+// reduced to a 6x6x6 cube, which keeps the encoder simple at some cost in
+// quality. Transparent pixels are left unpainted.
+func sixelImage(img image.Image, width int) string {
+	b := img.Bounds()
+	w := min(b.Dx(), width)
+	h := max(b.Dy()*w/b.Dx(), 1)
+	index := func(x, y int) int {
+		r, g, bl, a := img.At(b.Min.X+x*b.Dx()/w, b.Min.Y+y*b.Dy()/h).RGBA()
+		if a < 0x8000 {
+			return -1
+		}
+		return int(r*5/0xffff)*36 + int(g*5/0xffff)*6 + int(bl*5/0xffff)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	for i := 0; i < 216; i++ {
+		fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+	}
+	for band := 0; band < h; band += 6 {
+		// Collect the sixel bits of each colour used in this band.
+		bits := map[int][]byte{}
+		var colours []int
+		for x := 0; x < w; x++ {
+			for dy := 0; dy < 6 && band+dy < h; dy++ {
+				c := index(x, band+dy)
+				if c < 0 {
+					continue
+				}
+				if bits[c] == nil {
+					bits[c] = make([]byte, w)
+					colours = append(colours, c)
+				}
+				bits[c][x] |= 1 << dy
+			}
+		}
+		for i, c := range colours {
+			if i > 0 {
+				out.WriteString("$")
+			}
+			fmt.Fprintf(&out, "#%d", c)
+			writeSixels(&out, bits[c])
+		}
+		out.WriteString("-")
+	}
+	out.WriteString("\x1b\\")
+	return out.String()
+}
+
+// writeSixels writes one colour's row of sixels with run-length encoding.
+func writeSixels(out *strings.Builder, row []byte) {
+	for x := 0; x < len(row); {
+		run := 1
+		for x+run < len(row) && row[x+run] == row[x] {
+			run++
+		}
+		ch := rune(row[x] + 63)
+		if run > 3 {
+			fmt.Fprintf(out, "!%d%c", run, ch)
+		} else {
+			out.WriteString(strings.Repeat(string(ch), run))
+		}
+		x += run
+	}
+}
+
+// imageDisplay draws an image on the terminal while the TUI is suspended and
+// waits for Enter. It implements tea.ExecCommand.
+type imageDisplay struct {
+	image  string // escape sequences that draw the image
+	clear  string // escape sequences that remove it again
+	stdin  io.Reader
+	stdout io.Writer
+}
+
+func (d *imageDisplay) SetStdin(r io.Reader)  { d.stdin = r }
+func (d *imageDisplay) SetStdout(w io.Writer) { d.stdout = w }
+func (d *imageDisplay) SetStderr(io.Writer)   {}
+
+func (d *imageDisplay) Run() error {
+	fmt.Fprint(d.stdout, "\x1b[2J\x1b[H")
+	fmt.Fprint(d.stdout, d.image)
+	fmt.Fprint(d.stdout, "\r\n\r\nPress Enter to return.")
+	_, err := bufio.NewReader(d.stdin).ReadString('\n')
+	fmt.Fprint(d.stdout, d.clear)
+	return err
+}
+
+// imageShown is sent when the image display opened from the result view is
+// closed.
+type imageShown struct {
+	err error
+}
+
+// showImage suspends the TUI and draws the image to fit the terminal, using
+// the terminal's graphics protocol if it has one.
+func showImage(img image.Image, data []byte, width, height int) tea.Cmd {
+	// Leave room for the prompt below the image.
+	cols, rows := fitCells(img.Bounds(), width, height-3)
+	d := &imageDisplay{}
+	switch detectImageProtocol() {
+	case "kitty":
+		// Delete only this image, not the previews in the result view.
+		id := newKittyImageID()
+		d.image = kittyImage(img, id, cols, rows)
+		d.clear = fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", id)
+	case "iterm":
+		d.image = itermImage(data, cols, rows)
+	case "sixel":
+		// Assume cells are about 10 pixels wide.
+		d.image = sixelImage(img, cols*10)
+	default:
+		d.image = strings.ReplaceAll(halfBlocks(img, cols, rows), "\n", "\r\n")
+	}
+	return tea.Exec(d, func(err error) tea.Msg {
+		return imageShown{err: err}
+	})
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list [tools|resources|prompts|templates] [transport] [target]",
+	Short: "List the tools, resources, prompts or resource templates of a server",
+	Long: `List a server's catalog without the TUI.
+
+The table output is a short summary. The json and yaml outputs include every
+field the server returns, such as input schemas and annotations.`,
+	Example: `  mcp-cli list tools stdio "python server.py"
+  mcp-cli list resources http http://localhost:8080/mcp -o json`,
+	Args:      cobra.ExactArgs(3),
+	ValidArgs: []string{"tools", "resources", "prompts", "templates"},
+	Run: func(cmd *cobra.Command, args []string) {
+		what, kind, target := args[0], args[1], args[2]
+		output, _ := cmd.Flags().GetString("output")
+		if output != "table" && output != "json" && output != "yaml" {
+			log.Fatalf("Unknown output format %q (want table, json or yaml)", output)
+		}
+
+		ctx := context.Background()
+		session, err := connectHeadless(ctx, cmd, kind, target)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s server: %v", kind, err)
+		}
+		defer session.Close()
+
+		reqCtx, cancel := newRequestContext(ctx)
+		defer cancel()
+		if err := listCatalog(reqCtx, os.Stdout, session, what, output); err != nil {
+			log.Fatalf("Failed to list %s: %v", what, err)
+		}
+	},
+}
+
+// collect drains a paginated iterator from the session into a slice,
+// stopping at the first error.
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// listCatalog writes the server's items of the given kind to w.
+func listCatalog(ctx context.Context, w io.Writer, session *mcp.ClientSession, what, output string) error {
+	var items any
+	var rows [][]string
+	var header []string
+
+	switch what {
+	case "tools":
+		tools, err := collect(session.Tools(ctx, nil))
+		if err != nil {
+			return err
+		}
+		items = tools
+		header = []string{"NAME", "ARGUMENTS", "HINTS", "DESCRIPTION"}
+		for _, t := range tools {
+			rows = append(rows, []string{t.Name, toolArgSummary(t), toolHintSummary(t.Annotations), firstLine(t.Description)})
+		}
+	case "resources":
+		resources, err := collect(session.Resources(ctx, nil))
+		if err != nil {
+			return err
+		}
+		items = resources
+		header = []string{"NAME", "URI", "MIME TYPE", "DESCRIPTION"}
+		for _, r := range resources {
+			rows = append(rows, []string{r.Name, r.URI, r.MIMEType, firstLine(r.Description)})
+		}
+	case "prompts":
+		prompts, err := collect(session.Prompts(ctx, nil))
+		if err != nil {
+			return err
+		}
+		items = prompts
+		header = []string{"NAME", "ARGUMENTS", "DESCRIPTION"}
+		for _, p := range prompts {
+			rows = append(rows, []string{p.Name, promptArgSummary(p), firstLine(p.Description)})
+		}
+	case "templates":
+		templates, err := collect(session.ResourceTemplates(ctx, nil))
+		if err != nil {
+			return err
+		}
+		items = templates
+		header = []string{"NAME", "URI TEMPLATE", "MIME TYPE", "DESCRIPTION"}
+		for _, t := range templates {
+			rows = append(rows, []string{t.Name, t.URITemplate, t.MIMEType, firstLine(t.Description)})
+		}
+	default:
+		return fmt.Errorf("unknown catalog %q (want tools, resources, prompts or templates)", what)
+	}
+
+	switch output {
+	case "json":
+		return writeJSON(w, items)
+	case "yaml":
+		return writeYAML(w, items)
+	default:
+		return writeTable(w, header, rows)
+	}
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeYAML writes v to w as YAML. The value is round-tripped through JSON
+// first so that field names match the protocol rather than the Go structs.
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(generic); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// writeTable writes the rows to w as tab-aligned columns.
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// toolArgSummary lists the tool's input properties, marking required ones
+// with a trailing asterisk.
+func toolArgSummary(tool *mcp.Tool) string {
+	if tool.InputSchema == nil {
+		return ""
+	}
+	required := make(map[string]bool)
+	for _, name := range tool.InputSchema.Required {
+		required[name] = true
+	}
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(tool.InputSchema.Properties)) {
+		if required[name] {
+			name += "*"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// promptArgSummary lists the prompt's arguments, marking required ones with a
+// trailing asterisk.
+func promptArgSummary(prompt *mcp.Prompt) string {
+	var names []string
+	for _, arg := range prompt.Arguments {
+		name := arg.Name
+		if arg.Required {
+			name += "*"
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// toolHintSummary describes the behavioural hints set in a tool's annotations.
+func toolHintSummary(a *mcp.ToolAnnotations) string {
+	if a == nil {
+		return ""
+	}
+	var hints []string
+	if a.ReadOnlyHint {
+		hints = append(hints, "read-only")
+	}
+	if a.DestructiveHint != nil && *a.DestructiveHint {
+		hints = append(hints, "destructive")
+	}
+	if a.IdempotentHint {
+		hints = append(hints, "idempotent")
+	}
+	if a.OpenWorldHint != nil && *a.OpenWorldHint {
+		hints = append(hints, "open-world")
+	}
+	return strings.Join(hints, ", ")
+}
+
+// firstLine returns the first line of s, for single-line table cells.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(s), "\n")
+	return line
+}
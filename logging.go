@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// logLevels are the MCP logging levels, from least to most severe.
+var logLevels = []mcp.LoggingLevel{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+var logLevelColors = map[mcp.LoggingLevel]lipgloss.Color{
+	"debug":     lipgloss.Color("244"), // Gray
+	"info":      lipgloss.Color("39"),  // Blue
+	"notice":    lipgloss.Color("42"),  // Green
+	"warning":   lipgloss.Color("214"), // Orange
+	"error":     lipgloss.Color("196"), // Red
+	"critical":  lipgloss.Color("196"),
+	"alert":     lipgloss.Color("196"),
+	"emergency": lipgloss.Color("196"),
+}
+
+// serverLog is sent when the server emits a notifications/message log entry.
+type serverLog struct {
+	params *mcp.LoggingMessageParams
+}
+
+// logLevelResult represents the outcome of a logging/setLevel request
+type logLevelResult struct {
+	level mcp.LoggingLevel
+	err   error
+}
+
+// validLogLevel reports whether level is one of the MCP logging levels.
+func validLogLevel(level string) bool {
+	return slices.Contains(logLevels, mcp.LoggingLevel(level))
+}
+
+// formatServerLog renders a log entry as "[level] logger: data".
+func formatServerLog(p *mcp.LoggingMessageParams) string {
+	data, ok := p.Data.(string)
+	if !ok {
+		b, err := json.Marshal(p.Data)
+		if err != nil {
+			data = fmt.Sprintf("%v", p.Data)
+		} else {
+			data = string(b)
+		}
+	}
+	if p.Logger != "" {
+		return fmt.Sprintf("[%s] %s: %s", p.Level, p.Logger, data)
+	}
+	return fmt.Sprintf("[%s] %s", p.Level, data)
+}
+
+// nextLogLevel returns the level after the current one, wrapping around.
+func nextLogLevel(level mcp.LoggingLevel) mcp.LoggingLevel {
+	i := slices.Index(logLevels, level)
+	return logLevels[(i+1)%len(logLevels)]
+}
+
+// setLogLevelCmd returns a tea.Cmd that asks the server to send log entries
+// at level and above.
+func (m *AppModel) setLogLevelCmd(level mcp.LoggingLevel) tea.Cmd {
+	session, ctx := m.session, m.ctx
+	return func() tea.Msg {
+		err := session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: level})
+		return logLevelResult{level: level, err: err}
+	}
+}
+
+// setServerLogLevel sets the server's log level for a headless session.
+func setServerLogLevel(ctx context.Context, session *mcp.ClientSession, level string) error {
+	if !validLogLevel(level) {
+		return fmt.Errorf("unknown log level %q (want one of %v)", level, logLevels)
+	}
+	return session.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: mcp.LoggingLevel(level)})
+}
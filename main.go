@@ -8,22 +8,31 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
-	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/spf13/cobra"
 )
 
-var verbose bool
+var (
+	verbose        bool
+	requestTimeout time.Duration
+
+	samplingEndpoint string
+	samplingModel    string
+	samplingKeyEnv   string
+	noValidate       bool
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "mcp-cli",
@@ -40,30 +49,77 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", 0, "Timeout for each request to the server, e.g. 30s (0 means no timeout)")
+	rootCmd.PersistentFlags().BoolVar(&noValidate, "no-validate", false, "Send tool arguments without checking them against the tool's input schema")
+	rootCmd.PersistentFlags().StringVar(&samplingEndpoint, "sampling-endpoint", "", "Forward sampling requests to this OpenAI-compatible API, e.g. http://localhost:11434/v1")
+	rootCmd.PersistentFlags().StringVar(&samplingKeyEnv, "sampling-api-key-env", "", "Environment variable holding the API key for --sampling-endpoint, e.g. OPENAI_API_KEY")
+	rootCmd.PersistentFlags().StringVar(&samplingModel, "sampling-model", "", "Model for forwarded sampling requests when the server gives no model hint")
+	rootCmd.PersistentFlags().BoolVar(&noHistory, "no-history", false, "Do not load or save the call history")
+	rootCmd.PersistentFlags().StringVar(&presetCollection, "collection", "", "Collection file that presets saved in the TUI are added to (default presets.yaml in the collections directory)")
+	rootCmd.PersistentFlags().Var(imageProtocolFlag{}, "image-protocol", "How to draw images from tool results: auto, kitty, iterm, sixel or halfblocks (iterm and sixel previews use half blocks)")
 	stdioCmd.Flags().StringSliceP("env", "e", []string{}, "Environment variables to pass to the command")
+	addStdioFlags(stdioCmd)
 	sseCmd.Flags().StringSliceP("header", "H", []string{}, "Headers to pass to the server")
 	httpCmd.Flags().StringSliceP("header", "H", []string{}, "Headers to pass to the server")
+	addRootFlag(stdioCmd)
+	addRootFlag(sseCmd)
+	addRootFlag(httpCmd)
+	addRootFlag(connectCmd)
+	connectCmd.Flags().String("config", "", "Servers config file (default servers.yaml in the mcp-cli config directory)")
+	connectCmd.Flags().String("from-config", "", "Read the servers from a Claude Desktop or VS Code MCP config file")
+	connectCmd.Flags().String("server", "", "Name of the server to connect to, as an alternative to the argument")
+	addConnectionFlags(callCmd)
+	callCmd.Flags().StringArray("arg", []string{}, "Tool argument as key=value, coerced using the tool's input schema")
+	callCmd.Flags().String("args-json", "", "Tool arguments as a JSON object; --arg values override its keys")
+	callCmd.Flags().String("save-dir", "", "Directory to save image, audio and embedded resource content to")
+	addConnectionFlags(runCmd)
+	runCmd.Flags().StringArray("request", []string{}, "Run only the request with this name; can be repeated")
+	runCmd.Flags().StringArray("var", []string{}, "Collection variable as name=value, overriding the collection's value")
+	runCmd.Flags().Bool("fail-fast", false, "Stop at the first request that fails")
+	addConnectionFlags(listCmd)
+	listCmd.Flags().StringP("output", "o", "table", "Output format: table, json or yaml")
+	addConnectionFlags(readCmd)
+	readCmd.Flags().StringP("output", "o", "", "File, or directory for several contents, to save the resource to")
 }
 
 var stdioCmd = &cobra.Command{
-	Use:   "stdio [command]",
+	Use:   "stdio [command] | stdio -- [command] [args...]",
 	Short: "Connect to an MCP server over stdio",
-	Args:  cobra.ExactArgs(1),
+	Long: `Start an MCP server as a subprocess and talk to it over stdin and stdout.
+
+The command is either one argument, which is split into words with shell-style
+quoting, or the words after --, which are used as they are.`,
+	Example: `  mcp-cli stdio "python '/path/with spaces/server.py' --debug"
+  mcp-cli stdio --cwd ~/projects/app --env-file .env -- node build/index.js`,
+	Args: cobra.MinimumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		command := args[0]
+		argv := args
+		if cmd.ArgsLenAtDash() < 0 && len(args) == 1 {
+			var err error
+			if argv, err = splitCommand(args[0]); err != nil {
+				log.Fatal(err)
+			}
+			if len(argv) == 0 {
+				log.Fatal("Empty stdio command")
+			}
+		}
+		command := quoteCommand(argv)
 		if verbose {
 			log.Printf("Command: %s", command)
 		}
 
-		env, _ := cmd.Flags().GetStringSlice("env")
+		roots, err := rootsFromFlags(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
 
 		ctx := context.Background()
-		client := mcp.NewClient(&mcp.Implementation{Name: "mcp-cli", Version: "v0.1.0"}, nil)
+		client := newClient(roots)
 
-		cmdParts := strings.Fields(command)
-		execCmd := exec.Command(cmdParts[0], cmdParts[1:]...)
-		execCmd.Env = append(os.Environ(), env...)
-		transport := &mcp.CommandTransport{Command: execCmd}
+		transport, err := newCommandTransport(argv, stdioOptionsFromFlags(cmd))
+		if err != nil {
+			log.Fatal(err)
+		}
 		session, err := client.Connect(ctx, transport, nil)
 		if err != nil {
 			log.Fatalf("Failed to connect to stdio server: %v", err)
@@ -74,7 +130,7 @@ var stdioCmd = &cobra.Command{
 			log.Println("Connected to stdio server")
 		}
 
-		handleSession(ctx, session)
+		handleSession(ctx, session, serverID("stdio", command), newClientRoots(client, roots))
 	},
 }
 
@@ -85,25 +141,19 @@ var sseCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		url := args[0]
 		headerStrings, _ := cmd.Flags().GetStringSlice("header")
+		roots, err := rootsFromFlags(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
 		ctx := context.Background()
 
+		client := newClient(roots)
 		connect := func() (*mcp.ClientSession, error) {
-			var httpClient *http.Client
-			if len(headerStrings) > 0 {
-				headers := parseHeaders(headerStrings)
-				httpClient = &http.Client{
-					Transport: &headerTransport{
-						base:    http.DefaultTransport,
-						headers: headers,
-					},
-				}
-			}
-			client := mcp.NewClient(&mcp.Implementation{Name: "mcp-cli", Version: "v0.1.0"}, nil)
-			transport := &mcp.SSEClientTransport{Endpoint: url, HTTPClient: httpClient}
+			transport := &mcp.SSEClientTransport{Endpoint: url, HTTPClient: newHTTPClient(headerStrings)}
 			return client.Connect(ctx, transport, nil)
 		}
 
-		runSessionWithReconnect(ctx, connect)
+		runSessionWithReconnect(ctx, connect, serverID("sse", url), newClientRoots(client, roots))
 	},
 }
 
@@ -114,28 +164,141 @@ var httpCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		url := args[0]
 		headerStrings, _ := cmd.Flags().GetStringSlice("header")
+		roots, err := rootsFromFlags(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
 		ctx := context.Background()
 
+		client := newClient(roots)
 		connect := func() (*mcp.ClientSession, error) {
-			var httpClient *http.Client
-			if len(headerStrings) > 0 {
-				headers := parseHeaders(headerStrings)
-				httpClient = &http.Client{
-					Transport: &headerTransport{
-						base:    http.DefaultTransport,
-						headers: headers,
-					},
-				}
-			}
-			client := mcp.NewClient(&mcp.Implementation{Name: "mcp-cli", Version: "v0.1.0"}, nil)
-			transport := &mcp.StreamableClientTransport{Endpoint: url, HTTPClient: httpClient}
+			transport := &mcp.StreamableClientTransport{Endpoint: url, HTTPClient: newHTTPClient(headerStrings)}
 			return client.Connect(ctx, transport, nil)
 		}
 
-		runSessionWithReconnect(ctx, connect)
+		runSessionWithReconnect(ctx, connect, serverID("http", url), newClientRoots(client, roots))
 	},
 }
 
+// newClient creates the MCP client used by every command, offering roots to
+// the server.
+func newClient(roots []*mcp.Root) *mcp.Client {
+	client := mcp.NewClient(&mcp.Implementation{Name: "mcp-cli", Version: "v0.1.0"}, clientOptions())
+	client.AddRoots(roots...)
+	return client
+}
+
+// newHeadlessClient returns a client for the commands that run without the
+// TUI. Nobody can answer elicitation requests there, and sampling is only
+// offered when requests can be forwarded to --sampling-endpoint, so that
+// servers fall back instead of failing.
+func newHeadlessClient(roots []*mcp.Root) *mcp.Client {
+	opts := clientOptions()
+	opts.ElicitationHandler = nil
+	if samplingEndpoint == "" {
+		opts.CreateMessageHandler = nil
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "mcp-cli", Version: "v0.1.0"}, opts)
+	client.AddRoots(roots...)
+	return client
+}
+
+// newStdioTransport returns a transport that launches command as a
+// subprocess. The command is split into words with shell-style quoting.
+func newStdioTransport(command string, opts stdioOptions) (*mcp.CommandTransport, error) {
+	argv, err := splitCommand(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(argv) == 0 {
+		return nil, fmt.Errorf("empty stdio command")
+	}
+	return newCommandTransport(argv, opts)
+}
+
+// newCommandTransport returns a transport that launches argv as a subprocess.
+func newCommandTransport(argv []string, opts stdioOptions) (*mcp.CommandTransport, error) {
+	env, err := opts.environ()
+	if err != nil {
+		return nil, err
+	}
+	execCmd := exec.Command(argv[0], argv[1:]...)
+	execCmd.Env = env
+	execCmd.Dir = opts.dir
+	return &mcp.CommandTransport{Command: execCmd}, nil
+}
+
+// newHTTPClient returns an http.Client that sends the given headers with
+// every request, or nil to use the transport's default client.
+func newHTTPClient(headerStrings []string) *http.Client {
+	if len(headerStrings) == 0 {
+		return nil
+	}
+	return &http.Client{
+		Transport: &headerTransport{
+			base:    http.DefaultTransport,
+			headers: parseHeaders(headerStrings),
+		},
+	}
+}
+
+// newTransport builds the transport for kind ("stdio", "sse" or "http"),
+// where target is the server command for stdio and the endpoint URL otherwise.
+func newTransport(kind, target string, opts stdioOptions, headerStrings []string) (mcp.Transport, error) {
+	switch kind {
+	case "stdio":
+		return newStdioTransport(target, opts)
+	case "sse":
+		return &mcp.SSEClientTransport{Endpoint: target, HTTPClient: newHTTPClient(headerStrings)}, nil
+	case "http":
+		return &mcp.StreamableClientTransport{Endpoint: target, HTTPClient: newHTTPClient(headerStrings)}, nil
+	default:
+		return nil, fmt.Errorf("unknown transport %q (want stdio, sse or http)", kind)
+	}
+}
+
+// addConnectionFlags registers the --env, --header and --root flags, and the
+// other flags for launching stdio servers, used by the headless commands,
+// which take the transport as an argument.
+func addConnectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceP("env", "e", []string{}, "Environment variables to pass to the command (stdio)")
+	addStdioFlags(cmd)
+	cmd.Flags().StringSliceP("header", "H", []string{}, "Headers to pass to the server (sse, http)")
+	addRootFlag(cmd)
+	cmd.Flags().String("server-log-level", "", "Ask the server to send log messages at this level and above to stderr")
+}
+
+// connectHeadless connects to the server described by kind and target using
+// the connection flags registered on cmd.
+func connectHeadless(ctx context.Context, cmd *cobra.Command, kind, target string) (*mcp.ClientSession, error) {
+	headerStrings, _ := cmd.Flags().GetStringSlice("header")
+	logLevel, _ := cmd.Flags().GetString("server-log-level")
+	if logLevel != "" && !validLogLevel(logLevel) {
+		return nil, fmt.Errorf("unknown server log level %q", logLevel)
+	}
+	roots, err := rootsFromFlags(cmd)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newTransport(kind, target, stdioOptionsFromFlags(cmd), headerStrings)
+	if err != nil {
+		return nil, err
+	}
+	if verbose {
+		log.Printf("Connecting to %s server: %s", kind, target)
+	}
+	session, err := newHeadlessClient(roots).Connect(ctx, transport, nil)
+	if err != nil {
+		return nil, err
+	}
+	if logLevel != "" {
+		if err := setServerLogLevel(ctx, session, logLevel); err != nil {
+			log.Printf("Failed to set server log level: %v", err)
+		}
+	}
+	return session, nil
+}
+
 // headerTransport is an http.RoundTripper that adds custom headers to each request.
 type headerTransport struct {
 	base    http.RoundTripper
@@ -165,7 +328,7 @@ func parseHeaders(headerStrings []string) http.Header {
 
 type connectFn func() (*mcp.ClientSession, error)
 
-func runSessionWithReconnect(ctx context.Context, connect connectFn) {
+func runSessionWithReconnect(ctx context.Context, connect connectFn, server string, roots *clientRoots) {
 	for {
 		log.Println("Attempting to connect to server...")
 		session, err := connect()
@@ -176,7 +339,7 @@ func runSessionWithReconnect(ctx context.Context, connect connectFn) {
 		}
 
 		log.Println("Connected to server.")
-		err = handleSession(ctx, session)
+		err = handleSession(ctx, session, server, roots)
 		session.Close()
 
 		if err != nil {
@@ -198,6 +361,16 @@ const (
 	resourceListView
 	resourceDetailView
 	promptListView
+	promptArgumentInputView
+	promptDetailView
+	templateListView
+	templateArgumentInputView
+	samplingView
+	elicitationView
+	rootsView
+	toolResultView
+	historyView
+	presetView
 )
 
 type focusedPanel int
@@ -215,66 +388,91 @@ type AppModel struct {
 	toolList         list.Model
 	resourceList     list.Model
 	promptList       list.Model
-	argInputs        []textinput.Model
-	argOrder         []string
-	argFocus         int
+	templateList     list.Model
+	argForm          *argForm
+	argRawMode       bool
+	argEditor        textarea.Model
+	argEditorErr     string
 	selectedTool     *mcp.Tool
 	tools            []*mcp.Tool
 	resources        []*mcp.Resource
 	prompts          []*mcp.Prompt
+	templates        []*mcp.ResourceTemplate
 	selectedResource *mcp.Resource
-	result           string
+	selectedTemplate *mcp.ResourceTemplate
+	templateVars     []string
+	templateInputs   []textinput.Model
+	templateFocus    int
+	selectedPrompt   *mcp.Prompt
+	promptArgInputs  []textinput.Model
+	promptArgFocus   int
+	resultTool       string
+	resultBlocks     []resultBlock
+	resultStructured structuredResult
+	resultPane       resultPane
+	resultArgs       map[string]any // arguments of the call shown in the result view
 	resourceResult   string
-	err              error
-	log              []string
-	width            int
-	height           int
-	debugViewport    viewport.Model
-}
-
-func initialModel(ctx context.Context, session *mcp.ClientSession) *AppModel {
-	var err error
-	var tools []*mcp.Tool
-	var resources []*mcp.Resource
-
-	// Iterate over the tools using range
-	for tool, iterErr := range session.Tools(ctx, nil) {
-		if iterErr != nil {
-			err = iterErr
-			break
-		}
-		tools = append(tools, tool)
-	}
+	resourceContents []*mcp.ResourceContents // contents shown in resourceResult, for diffs
+	promptResult     string
+	subscriptions    map[string]bool
+	resourceRefresh  bool
+	resourceDiff     string
+	detailFromResult bool // the resource detail view was opened from a tool result
+	serverLogLevel   mcp.LoggingLevel
+	callSeq          int
+	requestSeq       int
+	inFlight         *callProgress
+	pending          map[int]pendingRequest
+	callStatus       string
+
+	samplingQueue       []samplingRequest
+	samplingInput       textarea.Model
+	samplingReturnState viewState
+
+	elicitQueue       []elicitationRequest
+	elicitForm        *argForm
+	elicitReturnState viewState
+
+	roots      *clientRoots
+	rootsList  list.Model
+	rootInput  textinput.Model
+	addingRoot bool
+
+	history     *callHistory
+	historyList list.Model
+
+	collections  []*collection
+	presetList   list.Model
+	presetInput  textinput.Model
+	savingPreset bool
+
+	err           error
+	log           []string
+	width         int
+	height        int
+	debugViewport viewport.Model
+}
 
+func initialModel(ctx context.Context, session *mcp.ClientSession, server string, roots *clientRoots) *AppModel {
+	tools, err := collect(session.Tools(ctx, nil))
 	if err != nil {
 		return &AppModel{err: err}
 	}
 
-	var prompts []*mcp.Prompt
-	for prompt, iterErr := range session.Prompts(ctx, nil) {
-		if iterErr != nil {
-			err = iterErr
-			break
-		}
-		prompts = append(prompts, prompt)
-	}
-
+	prompts, err := collect(session.Prompts(ctx, nil))
 	if err != nil {
 		return &AppModel{err: err}
 	}
 
-	for resource, iterErr := range session.Resources(ctx, nil) {
-		if iterErr != nil {
-			err = iterErr
-			break
-		}
-		resources = append(resources, resource)
-	}
-
+	resources, err := collect(session.Resources(ctx, nil))
 	if err != nil {
 		return &AppModel{err: err}
 	}
 
+	// Not every server implements resources/templates/list, so a failure here
+	// only leaves the templates tab empty.
+	templates, templatesErr := collect(session.ResourceTemplates(ctx, nil))
+
 	toolItems := []list.Item{}
 	for _, tool := range tools {
 		toolItems = append(toolItems, item{title: tool.Name, desc: tool.Description, tool: tool})
@@ -290,12 +488,19 @@ func initialModel(ctx context.Context, session *mcp.ClientSession) *AppModel {
 		promptItems = append(promptItems, promptItem{title: prompt.Name, desc: prompt.Description, prompt: prompt})
 	}
 
+	templateItems := []list.Item{}
+	for _, template := range templates {
+		templateItems = append(templateItems, templateItem{title: template.Name, desc: template.URITemplate, template: template})
+	}
+
 	toolList := list.New(toolItems, list.NewDefaultDelegate(), 0, 0)
 	toolList.Title = "Select a tool to execute"
 	toolList.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
 			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "resources")),
 			key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "prompts")),
+			key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "roots")),
+			key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "history")),
 		}
 	}
 
@@ -305,6 +510,17 @@ func initialModel(ctx context.Context, session *mcp.ClientSession) *AppModel {
 		return []key.Binding{
 			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tools")),
 			key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "prompts")),
+			key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "templates")),
+		}
+	}
+
+	templateList := list.New(templateItems, list.NewDefaultDelegate(), 0, 0)
+	templateList.Title = "Select a resource template"
+	templateList.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tools")),
+			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "resources")),
+			key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "prompts")),
 		}
 	}
 
@@ -312,15 +528,18 @@ func initialModel(ctx context.Context, session *mcp.ClientSession) *AppModel {
 	promptList.Title = "Select a prompt"
 	promptList.AdditionalShortHelpKeys = func() []key.Binding {
 		return []key.Binding{
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "get prompt")),
 			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tools")),
 			key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "resources")),
 		}
 	}
 
+	history, historyErr := openHistory(server)
+
 	vp := viewport.New(1, 1) // Initial size, will be updated on WindowSizeMsg
 	vp.SetContent("Debug log will appear here...")
 
-	return &AppModel{
+	m := &AppModel{
 		state:         toolSelectionView,
 		focusedPanel:  mainPanelFocus,
 		ctx:           ctx,
@@ -328,11 +547,27 @@ func initialModel(ctx context.Context, session *mcp.ClientSession) *AppModel {
 		toolList:      toolList,
 		resourceList:  resourceList,
 		promptList:    promptList,
+		templateList:  templateList,
+		roots:         roots,
+		rootsList:     newRootsList(roots),
+		history:       history,
+		historyList:   newHistoryList(history),
+		presetList:    newPresetList(),
 		tools:         tools,
 		resources:     resources,
 		prompts:       prompts,
+		templates:     templates,
+		subscriptions: make(map[string]bool),
+		pending:       make(map[int]pendingRequest),
 		debugViewport: vp,
 	}
+	if templatesErr != nil {
+		m.logf("Failed to list resource templates: %v", templatesErr)
+	}
+	if historyErr != nil {
+		m.logf("Failed to load call history: %v", historyErr)
+	}
+	return m
 }
 
 type item struct {
@@ -362,6 +597,15 @@ func (i promptItem) Title() string       { return i.title }
 func (i promptItem) Description() string { return i.desc }
 func (i promptItem) FilterValue() string { return i.title }
 
+type templateItem struct {
+	title, desc string
+	template    *mcp.ResourceTemplate
+}
+
+func (i templateItem) Title() string       { return i.title }
+func (i templateItem) Description() string { return i.desc }
+func (i templateItem) FilterValue() string { return i.title }
+
 func (m *AppModel) logf(format string, a ...any) {
 	m.log = append(m.log, fmt.Sprintf(format, a...))
 	m.debugViewport.SetContent(strings.Join(m.log, "\n"))
@@ -386,26 +630,175 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, cmd
 
 	case toolResult:
+		recordCmd := m.recordCall(msg.entry)
+		if m.inFlight == nil || m.inFlight.token != msg.token {
+			// Another call was started while this one was running; only
+			// the latest is shown.
+			m.logf("Ignoring the result of an earlier call to '%s'", msg.entry.Tool)
+			return m, recordCmd
+		}
+		m.inFlight = nil
+		if status := requestStatus(msg.err); status != "" {
+			m.logf("Result:\n========\n%s", status)
+			m.callStatus = status
+			return m, recordCmd
+		}
 		if msg.err != nil {
-			m.err = msg.err
-			return m, tea.Quit
+			// Protocol errors, such as invalid params sent with --no-validate,
+			// end the call but not the session.
+			m.logf("Result:\n========\nCall failed: %v", msg.err)
+			m.callStatus = fmt.Sprintf("Call failed: %v", msg.err)
+			return m, recordCmd
 		}
 		if verbose {
 			m.logf("Tool result received")
 		}
 		m.logf("Result:\n========\n%s", msg.result)
-		m.result = msg.result
-		return m, nil
+		if msg.structured.content != "" {
+			m.logf("Structured content:\n%s", msg.structured.content)
+		}
+		for _, line := range msg.structured.summary() {
+			m.logf("%s", line)
+		}
+		// The user may have moved on to another tool while the call ran.
+		m.resultTool = msg.entry.Tool
+		m.resultArgs = msg.entry.Arguments
+		m.resultBlocks = msg.blocks
+		m.resultStructured = msg.structured
+		m.resultPane.reset()
+		// Only show the result if the user is still waiting on the call.
+		if m.state == argumentInputView || m.state == toolSelectionView || m.state == toolResultView || m.state == historyView {
+			m.state = toolResultView
+		}
+		return m, recordCmd
 
 	case resourceResult:
+		if status := requestStatus(msg.err); status != "" {
+			m.logf("Reading resource: %s", status)
+			m.resourceResult = status
+			m.resourceRefresh = false
+			return m, nil
+		}
 		if msg.err != nil {
-			m.err = msg.err
-			return m, tea.Quit
+			// The server may reject a URI, for example a template expanded
+			// with a typo; show why and stay in the session.
+			m.logf("Reading resource failed: %v", msg.err)
+			m.resourceResult = fmt.Sprintf("Error: %v", msg.err)
+			m.resourceContents = nil
+			m.resourceDiff = ""
+			m.resourceRefresh = false
+			return m, nil
 		}
 		if verbose {
 			m.logf("Resource result received")
 		}
+		m.resourceDiff = ""
+		if m.resourceRefresh {
+			m.resourceDiff = renderDiff(diffText(m.resourceContents), diffText(msg.contents))
+			m.resourceRefresh = false
+		}
 		m.resourceResult = msg.result
+		m.resourceContents = msg.contents
+		return m, nil
+
+	case resourceUpdated:
+		m.logf("Resource updated: %s", msg.uri)
+		if m.state == resourceDetailView && m.selectedResource.URI == msg.uri {
+			m.resourceRefresh = true
+			return m, m.readResourceCmd()
+		}
+		return m, nil
+
+	case listChanged:
+		if verbose {
+			m.logf("Server reported %s list changed", msg.kind)
+		}
+		return m, m.refreshListCmd(msg.kind)
+
+	case listRefreshed:
+		return m, m.applyRefresh(msg)
+
+	case serverLog:
+		style := lipgloss.NewStyle().Foreground(logLevelColors[msg.params.Level])
+		m.logf("%s", style.Render(formatServerLog(msg.params)))
+		return m, nil
+
+	case logLevelResult:
+		if msg.err != nil {
+			m.logf("Failed to set server log level to %s: %v", msg.level, msg.err)
+			return m, nil
+		}
+		m.serverLogLevel = msg.level
+		m.logf("Server log level set to %s", msg.level)
+		return m, nil
+
+	case historySaved:
+		if msg.err != nil {
+			m.logf("Failed to save call history: %v", msg.err)
+		}
+		return m, nil
+
+	case progressUpdate:
+		m.updateProgress(msg)
+		return m, nil
+
+	case progressTick:
+		if m.inFlight == nil {
+			return m, nil
+		}
+		return m, progressTickCmd()
+
+	case samplingRequest:
+		return m, m.queueSampling(msg)
+
+	case elicitationRequest:
+		return m, m.queueElicitation(msg)
+
+	case elicitationAbandoned:
+		m.logf("Server stopped waiting for input")
+		m.dropElicitation(msg.reply)
+		return m, nil
+
+	case imageShown:
+		if msg.err != nil {
+			m.logf("Image display failed: %v", msg.err)
+		}
+		return m, nil
+
+	case argsEdited:
+		return m, m.applyEditedArgs(msg)
+
+	case samplingLog:
+		m.logf("%s", msg.text)
+		return m, nil
+
+	case samplingAbandoned:
+		m.logf("Server stopped waiting for a sampling reply")
+		return m, m.dropSampling(msg.reply)
+
+	case subscriptionResult:
+		if msg.err != nil {
+			m.logf("Failed to change subscription to %s: %v", msg.uri, msg.err)
+			return m, nil
+		}
+		m.subscriptions[msg.uri] = msg.subscribed
+		if msg.subscribed {
+			m.logf("Subscribed to %s", msg.uri)
+		} else {
+			m.logf("Unsubscribed from %s", msg.uri)
+		}
+		return m, nil
+
+	case promptResult:
+		if msg.err != nil {
+			m.logf("Error getting prompt '%s': %v", m.selectedPrompt.Name, msg.err)
+			m.promptResult = fmt.Sprintf("Error: %v", msg.err)
+			return m, nil
+		}
+		if verbose {
+			m.logf("Prompt result received")
+		}
+		m.promptResult = msg.result
 		return m, nil
 
 	case tea.KeyMsg:
@@ -422,14 +815,44 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 		case tea.KeyEsc:
-			if m.state == resourceDetailView {
+			if m.state == samplingView {
+				// The server is waiting for an answer; use ctrl+r to reject.
+				return m, nil
+			}
+			if m.state == elicitationView {
+				return m.updateElicitationView(msg)
+			}
+			if m.state == rootsView && m.addingRoot {
+				return m.updateRootsView(msg)
+			}
+			if m.state == presetView {
+				return m.updatePresetView(msg)
+			}
+			if m.state == toolResultView && m.resultPane.prompt != noPrompt {
+				return m.updateToolResultView(msg)
+			}
+			if m.state == resourceDetailView && m.detailFromResult {
+				m.detailFromResult = false
+				m.state = toolResultView
+			} else if m.state == resourceDetailView && m.selectedTemplate != nil {
+				m.state = templateListView
+			} else if m.state == resourceDetailView || m.state == templateListView {
 				m.state = resourceListView
+			} else if m.state == templateArgumentInputView {
+				m.state = templateListView
+			} else if m.state == promptArgumentInputView || m.state == promptDetailView {
+				m.state = promptListView
 			} else {
 				m.state = toolSelectionView
 			}
 			return m, nil
 		case tea.KeyCtrlC:
 			return m, tea.Quit
+		case tea.KeyCtrlX:
+			m.cancelRequests()
+			return m, nil
+		case tea.KeyCtrlL:
+			return m, m.setLogLevelCmd(nextLogLevel(m.serverLogLevel))
 		}
 	}
 
@@ -449,8 +872,28 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.updatePromptListView(msg)
 	case argumentInputView:
 		return m.updateArgumentInputView(msg)
+	case promptArgumentInputView:
+		return m.updatePromptArgumentInputView(msg)
+	case templateListView:
+		return m.updateTemplateListView(msg)
+	case templateArgumentInputView:
+		return m.updateTemplateArgumentInputView(msg)
 	case resourceDetailView:
+		return m.updateResourceDetailView(msg)
+	case promptDetailView:
 		return m, nil
+	case toolResultView:
+		return m.updateToolResultView(msg)
+	case historyView:
+		return m.updateHistoryView(msg)
+	case presetView:
+		return m.updatePresetView(msg)
+	case samplingView:
+		return m.updateSamplingView(msg)
+	case elicitationView:
+		return m.updateElicitationView(msg)
+	case rootsView:
+		return m.updateRootsView(msg)
 	}
 
 	return m, nil
@@ -468,8 +911,17 @@ func (m *AppModel) updateToolSelectionView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "p":
 			m.state = promptListView
 			return m, nil
+		case "o":
+			m.state = rootsView
+			return m, nil
+		case "c":
+			m.state = historyView
+			return m, nil
 		case "enter":
-			selectedItem := m.toolList.SelectedItem().(item)
+			selectedItem, ok := m.toolList.SelectedItem().(item)
+			if !ok {
+				return m, nil
+			}
 			m.selectedTool = selectedItem.tool
 
 			if m.selectedTool.InputSchema != nil && len(m.selectedTool.InputSchema.Properties) > 0 {
@@ -477,28 +929,14 @@ func (m *AppModel) updateToolSelectionView(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.logf("State change: toolSelectionView -> argumentInputView")
 				}
 				m.state = argumentInputView
-				m.argInputs = []textinput.Model{}
-				m.argOrder = []string{}
-				for name := range m.selectedTool.InputSchema.Properties {
-					m.argOrder = append(m.argOrder, name)
-				}
-				sort.Strings(m.argOrder)
-
-				for _, name := range m.argOrder {
-					prop := m.selectedTool.InputSchema.Properties[name]
-					ti := textinput.New()
-					ti.Placeholder = prop.Description
-					ti.Focus()
-					ti.CharLimit = 256
-					ti.Width = 50
-					m.argInputs = append(m.argInputs, ti)
-				}
-				m.argInputs[0].Focus()
+				m.argForm = newArgForm(m.selectedTool.InputSchema)
+				m.argRawMode = false
 			} else {
 				if verbose {
 					m.logf("No arguments needed, calling tool directly")
 				}
-				return m.callTool()
+				m.argForm = nil
+				return m.callTool(map[string]any{})
 			}
 		}
 	}
@@ -518,6 +956,31 @@ func (m *AppModel) updatePromptListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			m.state = resourceListView
 			return m, nil
+		case "enter":
+			selectedItem, ok := m.promptList.SelectedItem().(promptItem)
+			if !ok {
+				return m, nil
+			}
+			m.selectedPrompt = selectedItem.prompt
+
+			if len(m.selectedPrompt.Arguments) > 0 {
+				if verbose {
+					m.logf("State change: promptListView -> promptArgumentInputView")
+				}
+				m.state = promptArgumentInputView
+				m.promptArgInputs = []textinput.Model{}
+				m.promptArgFocus = 0
+				for _, arg := range m.selectedPrompt.Arguments {
+					ti := textinput.New()
+					ti.Placeholder = arg.Description
+					ti.CharLimit = 256
+					ti.Width = 50
+					m.promptArgInputs = append(m.promptArgInputs, ti)
+				}
+				m.promptArgInputs[0].Focus()
+				return m, nil
+			}
+			return m.getPrompt()
 		}
 	}
 
@@ -536,9 +999,16 @@ func (m *AppModel) updateResourceListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "p":
 			m.state = promptListView
 			return m, nil
+		case "u":
+			m.state = templateListView
+			return m, nil
 		case "enter":
-			selectedItem := m.resourceList.SelectedItem().(resourceItem)
+			selectedItem, ok := m.resourceList.SelectedItem().(resourceItem)
+			if !ok {
+				return m, nil
+			}
 			m.selectedResource = selectedItem.resource
+			m.selectedTemplate = nil
 			m.state = resourceDetailView
 			return m, m.readResourceCmd()
 		}
@@ -548,44 +1018,70 @@ func (m *AppModel) updateResourceListView(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *AppModel) updateArgumentInputView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.argRawMode {
+		return m.updateArgumentEditor(msg)
+	}
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if !ok {
 		return m, nil
 	}
 
+	switch keyMsg.String() {
+	case "ctrl+t":
+		return m, m.openArgumentEditor()
+	case "ctrl+o":
+		return m, m.editArgsExternally()
+	case "ctrl+p":
+		return m, m.openPresets()
+	}
+
 	if keyMsg.Type == tea.KeyEnter {
-		if m.argFocus == len(m.argInputs)-1 {
+		if m.argForm.AtLast() {
+			if !noValidate && !m.argForm.Validate(m.selectedTool.InputSchema) {
+				m.logf("Arguments for '%s' are not valid; fix the marked fields or use --no-validate", m.selectedTool.Name)
+				return m, nil
+			}
 			if verbose {
 				m.logf("Last argument input, calling tool")
 			}
-			return m.callTool()
-		}
-		m.argFocus++
-		for i := range m.argInputs {
-			if i == m.argFocus {
-				m.argInputs[i].Focus()
-			} else {
-				m.argInputs[i].Blur()
+			args, err := m.argForm.Value()
+			if err != nil {
+				m.logf("%v", err)
 			}
+			return m.callTool(args)
 		}
+		m.argForm.Next()
 		return m, nil
 	}
 
-	if keyMsg.Type == tea.KeyTab {
-		m.argFocus = (m.argFocus + 1) % len(m.argInputs)
-		for i := range m.argInputs {
-			if i == m.argFocus {
-				m.argInputs[i].Focus()
-			} else {
-				m.argInputs[i].Blur()
-			}
+	return m, m.argForm.Update(msg)
+}
+
+// focusInput focuses the input at index focus and blurs all others.
+func focusInput(inputs []textinput.Model, focus int) {
+	for i := range inputs {
+		if i == focus {
+			inputs[i].Focus()
+		} else {
+			inputs[i].Blur()
 		}
-		return m, nil
 	}
+}
 
-	var cmd tea.Cmd
-	m.argInputs[m.argFocus], cmd = m.argInputs[m.argFocus].Update(msg)
-	return m, cmd
+// mainPanelSize returns the width of the main panel and the height left for
+// its content.
+func (m *AppModel) mainPanelSize() (width, height int) {
+	width = m.width - m.width/3
+
+	// Reserve room below the content for the progress or status of a call.
+	height = m.height - 2
+	if m.inFlight != nil {
+		height -= lipgloss.Height(m.progressView(width-4)) + 1
+	} else if m.callStatus != "" {
+		// Errors from the server can be long enough to wrap.
+		height -= lipgloss.Height(lipgloss.NewStyle().Width(width-2).Render(m.callStatus)) + 1
+	}
+	return width, height
 }
 
 func (m AppModel) View() string {
@@ -594,38 +1090,150 @@ func (m AppModel) View() string {
 	}
 
 	debugPanelWidth := m.width / 3
-	mainPanelWidth := m.width - debugPanelWidth
+	mainPanelWidth, contentHeight := m.mainPanelSize()
+	var progress string
+	if m.inFlight != nil {
+		progress = m.progressView(mainPanelWidth - 4)
+	}
 
 	var mainContent strings.Builder
 	switch m.state {
 	case toolSelectionView:
-		m.toolList.SetSize(mainPanelWidth-2, m.height-2)
+		m.toolList.SetSize(mainPanelWidth-2, contentHeight)
 		mainContent.WriteString(m.toolList.View())
 	case resourceListView:
-		m.resourceList.SetSize(mainPanelWidth-2, m.height-2)
+		m.resourceList.SetSize(mainPanelWidth-2, contentHeight)
 		mainContent.WriteString(m.resourceList.View())
 	case promptListView:
-		m.promptList.SetSize(mainPanelWidth-2, m.height-2)
+		m.promptList.SetSize(mainPanelWidth-2, contentHeight)
 		mainContent.WriteString(m.promptList.View())
+	case templateListView:
+		m.templateList.SetSize(mainPanelWidth-2, contentHeight)
+		mainContent.WriteString(m.templateList.View())
 	case resourceDetailView:
 		var b strings.Builder
-		b.WriteString(fmt.Sprintf("Details for %s:\n\n", m.selectedResource.Name))
-		b.WriteString(m.resourceResult)
-		b.WriteString("\n\nPress Esc to go back to resource list.")
+		b.WriteString(fmt.Sprintf("Details for %s:", m.selectedResource.Name))
+		if m.subscriptions[m.selectedResource.URI] {
+			b.WriteString(" [subscribed]")
+		}
+		b.WriteString("\n\n")
+		if m.resourceDiff != "" {
+			b.WriteString("Updated by the server:\n\n")
+			b.WriteString(m.resourceDiff)
+		} else {
+			b.WriteString(m.resourceResult)
+		}
+		if m.canSubscribe() {
+			b.WriteString("\n\nPress s to subscribe or unsubscribe.")
+		}
+		if m.detailFromResult {
+			b.WriteString("\n\nPress Esc to go back to the tool result.")
+		} else if m.selectedTemplate != nil {
+			b.WriteString("\n\nPress Esc to go back to template list.")
+		} else {
+			b.WriteString("\n\nPress Esc to go back to resource list.")
+		}
 		mainContent.WriteString(b.String())
+	case samplingView:
+		var b strings.Builder
+		if len(m.samplingQueue) > 0 {
+			b.WriteString(fmt.Sprintf("Sampling request from the server (1 of %d):\n\n", len(m.samplingQueue)))
+			b.WriteString(samplingRequestView(m.samplingQueue[0].params))
+			b.WriteString("\nReply as assistant:\n")
+			m.samplingInput.SetWidth(mainPanelWidth - 4)
+			b.WriteString(m.samplingInput.View())
+			b.WriteString("\n\nPress Ctrl+S to send the reply, Ctrl+R to reject the request.")
+		}
+		mainContent.WriteString(b.String())
+	case elicitationView:
+		mainContent.WriteString(m.elicitationFormView())
+	case historyView:
+		m.historyList.SetSize(mainPanelWidth-2, contentHeight)
+		mainContent.WriteString(m.historyList.View())
+	case presetView:
+		if m.savingPreset {
+			mainContent.WriteString(fmt.Sprintf("Save the current arguments of %s as a preset:\n\n", m.selectedTool.Name))
+			mainContent.WriteString(m.presetInput.View())
+			mainContent.WriteString("\n\nPress Enter to save the preset, Esc to go back to the presets list.")
+		} else {
+			m.presetList.SetSize(mainPanelWidth-2, contentHeight)
+			mainContent.WriteString(m.presetList.View())
+		}
+	case rootsView:
+		if m.addingRoot {
+			mainContent.WriteString("Add a root (path[:name]):\n\n")
+			mainContent.WriteString(m.rootInput.View())
+			mainContent.WriteString("\n\nPress Enter to add the root, Esc to go back to the roots list.")
+		} else {
+			m.rootsList.SetSize(mainPanelWidth-2, contentHeight)
+			mainContent.WriteString(m.rootsList.View())
+		}
+	case templateArgumentInputView:
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("Enter variables for %s:\n\n", m.selectedTemplate.URITemplate))
+
+		for i, name := range m.templateVars {
+			b.WriteString(name + "\n")
+			b.WriteString(m.templateInputs[i].View())
+			b.WriteString("\n\n")
+		}
+		b.WriteString("\nPress Enter to submit, Tab to switch fields, Esc to go back to template list.")
+		mainContent.WriteString(b.String())
+	case toolResultView:
+		mainContent.WriteString(fmt.Sprintf("Result of %s:\n\n", m.resultTool))
+		mainContent.WriteString(m.resultPaneView())
 	case argumentInputView:
 		var b strings.Builder
 		b.WriteString(fmt.Sprintf("Enter arguments for %s:\n\n", m.selectedTool.Name))
 
-		for i, name := range m.argOrder {
-			b.WriteString(name + "\n")
-			b.WriteString(m.argInputs[i].View())
+		if m.argRawMode {
+			if m.argEditorErr != "" {
+				b.WriteString(fieldErrorStyle.Render(m.argEditorErr) + "\n\n")
+			}
+			m.argEditor.SetWidth(mainPanelWidth - 4)
+			b.WriteString(m.argEditor.View())
+			b.WriteString("\n\nPress Ctrl+S to submit, Ctrl+T to go back to the form, Ctrl+O to open the arguments in $EDITOR, Esc to go back to tool selection.")
+		} else {
+			b.WriteString(m.argForm.View())
+			b.WriteString("\nPress Enter to submit, Up/Down to switch fields, Left/Right or Space to change choices, Ctrl+T to edit as JSON, Ctrl+O to open the arguments in $EDITOR, Ctrl+P for presets, Esc to go back to tool selection.")
+		}
+		mainContent.WriteString(b.String())
+	case promptArgumentInputView:
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("Enter arguments for prompt %s:\n\n", m.selectedPrompt.Name))
+
+		for i, arg := range m.selectedPrompt.Arguments {
+			b.WriteString(arg.Name)
+			if arg.Required {
+				b.WriteString(" *")
+			}
+			b.WriteString("\n")
+			b.WriteString(m.promptArgInputs[i].View())
 			b.WriteString("\n\n")
 		}
-		b.WriteString("\nPress Enter to submit, Tab to switch fields, Esc to go back to tool selection.")
+		b.WriteString("* required\n")
+		b.WriteString("\nPress Enter to submit, Tab to switch fields, Esc to go back to prompt list.")
+		mainContent.WriteString(b.String())
+	case promptDetailView:
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("Prompt %s:\n\n", m.selectedPrompt.Name))
+		if m.promptResult == "" {
+			b.WriteString("Loading...")
+		} else {
+			b.WriteString(m.promptResult)
+		}
+		b.WriteString("\n\nPress Esc to go back to prompt list.")
 		mainContent.WriteString(b.String())
 	}
 
+	if progress != "" {
+		mainContent.WriteString("\n")
+		mainContent.WriteString(progress)
+	} else if m.callStatus != "" {
+		mainContent.WriteString("\n")
+		mainContent.WriteString(callStatusStyle.Render(m.callStatus))
+	}
+
 	mainPanelStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		Width(mainPanelWidth - 2).
@@ -650,120 +1258,143 @@ func (m AppModel) View() string {
 
 // toolResult represents the result of a tool call
 type toolResult struct {
-	result string
-	err    error
+	token      string // progress token of the call
+	result     string
+	blocks     []resultBlock
+	structured structuredResult
+	entry      *historyEntry
+	err        error
 }
 
 // resourceResult represents the result of a resource read
 type resourceResult struct {
+	result   string
+	contents []*mcp.ResourceContents
+	err      error
+}
+
+// promptResult represents the rendered messages of a prompt
+type promptResult struct {
 	result string
 	err    error
 }
 
 // callToolCmd returns a tea.Cmd that calls the tool
-func (m *AppModel) callToolCmd() tea.Cmd {
+func (m *AppModel) callToolCmd(ctx context.Context, cancel context.CancelFunc, progressToken string, args map[string]any) tea.Cmd {
+	prettyArgs, err := json.MarshalIndent(args, "", "  ")
+	if err != nil {
+		m.logf("Error marshalling args: %v", err)
+	}
+	m.logf("========\nCalling tool '%s' with args:\n%s", m.selectedTool.Name, string(prettyArgs))
+
+	params := &mcp.CallToolParams{
+		Meta:      mcp.Meta{}, // SetProgressToken only adds to an existing map
+		Name:      m.selectedTool.Name,
+		Arguments: args,
+	}
+	params.SetProgressToken(progressToken)
+	session := m.session
+	tool := m.selectedTool
 	return func() tea.Msg {
-		args := make(map[string]any)
-		for i, name := range m.argOrder {
-			valueStr := m.argInputs[i].Value()
-			var finalValue any = valueStr // Default to string
-
-			if prop, ok := m.selectedTool.InputSchema.Properties[name]; ok {
-				switch prop.Type {
-				case "number":
-					if valueStr == "" {
-						finalValue = 0
-						continue
-					}
-					f, err := strconv.ParseFloat(valueStr, 64)
-					if err == nil {
-						finalValue = f
-					} else {
-						m.logf("Error converting arg '%s' to number: %v", name, err)
-					}
-				case "integer":
-					if valueStr == "" {
-						finalValue = 0
-						continue
-					}
-					i, err := strconv.Atoi(valueStr)
-					if err == nil {
-						finalValue = i
-					} else {
-						m.logf("Error converting arg '%s' to integer: %v", name, err)
-					}
-				case "boolean":
-					if valueStr == "" {
-						finalValue = false
-						continue
-					}
-					b, err := strconv.ParseBool(valueStr)
-					if err == nil {
-						finalValue = b
-					} else {
-						m.logf("Error converting arg '%s' to boolean: %v", name, err)
-					}
-				}
-			}
-			args[name] = finalValue
+		defer cancel()
+		started := time.Now()
+		result, err := session.CallTool(ctx, params)
+		entry := &historyEntry{
+			Tool:      tool.Name,
+			Arguments: args,
+			Started:   started,
+			Duration:  time.Since(started),
+			Result:    result,
 		}
-
-		prettyArgs, err := json.MarshalIndent(args, "", "  ")
 		if err != nil {
-			m.logf("Error marshalling args: %v", err)
+			entry.Error = err.Error()
+			if status := requestStatus(err); status != "" {
+				entry.Error = status
+			}
+			return toolResult{token: progressToken, entry: entry, err: err}
 		}
-		m.logf("========\nCalling tool '%s' with args:\n%s", m.selectedTool.Name, string(prettyArgs))
 
-		params := &mcp.CallToolParams{
-			Name:      m.selectedTool.Name,
-			Arguments: args,
-		}
-		result, err := m.session.CallTool(m.ctx, params)
-		if err != nil {
-			return toolResult{err: err}
+		return toolResult{
+			token:      progressToken,
+			result:     formatToolResult(result),
+			blocks:     resultBlocks(result),
+			structured: checkStructuredContent(tool, result),
+			entry:      entry,
 		}
+	}
+}
 
-		var resultStr strings.Builder
-		if result.IsError {
-			resultStr.WriteString("Error:\n")
-		}
-
-		for _, content := range result.Content {
-			switch c := content.(type) {
-			case *mcp.TextContent:
-				var obj any
-				if json.Unmarshal([]byte(c.Text), &obj) == nil {
-					prettyJSON, err := json.MarshalIndent(obj, "", "  ")
-					if err == nil {
-						resultStr.WriteString(string(prettyJSON))
-						continue
-					}
-				}
-				resultStr.WriteString(c.Text)
-			default:
-				prettyJSON, err := json.MarshalIndent(c, "", "  ")
-				if err != nil {
-					resultStr.WriteString(fmt.Sprintf("Unsupported content type: %T", c))
-				} else {
-					resultStr.WriteString(string(prettyJSON))
-				}
-			}
+// coerceArg converts a raw string argument into the JSON type declared by its
+// property schema. Values for other types are returned unchanged.
+func coerceArg(prop *jsonschema.Schema, valueStr string) (any, error) {
+	if prop == nil {
+		return valueStr, nil
+	}
+	switch schemaType(prop) {
+	case "number":
+		return strconv.ParseFloat(valueStr, 64)
+	case "integer":
+		return strconv.Atoi(valueStr)
+	case "boolean":
+		return strconv.ParseBool(valueStr)
+	}
+	return valueStr, nil
+}
+
+// formatToolResult renders the content of a tool result as text, pretty
+// printing JSON text and describing other content blocks in one line.
+func formatToolResult(result *mcp.CallToolResult) string {
+	var resultStr strings.Builder
+	if result.IsError {
+		resultStr.WriteString("Error:\n")
+	}
+
+	for _, content := range result.Content {
+		if _, ok := content.(*mcp.TextContent); !ok && resultStr.Len() > 0 && !strings.HasSuffix(resultStr.String(), "\n") {
+			resultStr.WriteString("\n")
 		}
+		resultStr.WriteString(formatToolContent(content))
+	}
+
+	return resultStr.String()
+}
 
-		return toolResult{result: resultStr.String()}
+// formatToolContent renders a single content block of a tool result as text.
+func formatToolContent(content mcp.Content) string {
+	c, ok := content.(*mcp.TextContent)
+	if !ok {
+		return describeContent(content) + "\n"
+	}
+	var obj any
+	if json.Unmarshal([]byte(c.Text), &obj) == nil {
+		prettyJSON, err := json.MarshalIndent(obj, "", "  ")
+		if err == nil {
+			return string(prettyJSON)
+		}
 	}
+	return c.Text
 }
 
-func (m *AppModel) callTool() (tea.Model, tea.Cmd) {
-	return m, m.callToolCmd()
+func (m *AppModel) callTool(args map[string]any) (tea.Model, tea.Cmd) {
+	// A call that is still running keeps its ticks going.
+	ticking := m.inFlight != nil
+	token := m.startCall()
+	m.callStatus = ""
+	ctx, cancel := m.startRequest("tool")
+	if ticking {
+		return m, m.callToolCmd(ctx, cancel, token, args)
+	}
+	return m, tea.Batch(m.callToolCmd(ctx, cancel, token, args), progressTickCmd())
 }
 
 func (m *AppModel) readResourceCmd() tea.Cmd {
+	ctx, cancel := m.startRequest("resource")
+	params := &mcp.ReadResourceParams{
+		URI: m.selectedResource.URI,
+	}
 	return func() tea.Msg {
-		params := &mcp.ReadResourceParams{
-			URI: m.selectedResource.URI,
-		}
-		result, err := m.session.ReadResource(m.ctx, params)
+		defer cancel()
+		result, err := m.session.ReadResource(ctx, params)
 		if err != nil {
 			return resourceResult{err: err}
 		}
@@ -778,11 +1409,11 @@ func (m *AppModel) readResourceCmd() tea.Cmd {
 			}
 		}
 
-		return resourceResult{result: resultStr.String()}
+		return resourceResult{result: resultStr.String(), contents: result.Contents}
 	}
 }
 
-func handleSession(ctx context.Context, session *mcp.ClientSession) error {
+func handleSession(ctx context.Context, session *mcp.ClientSession, server string, roots *clientRoots) error {
 	if verbose {
 		f, err := tea.LogToFile("debug.log", "debug")
 		if err != nil {
@@ -791,8 +1422,10 @@ func handleSession(ctx context.Context, session *mcp.ClientSession) error {
 		}
 		defer f.Close()
 	}
-	model := initialModel(ctx, session)
+	model := initialModel(ctx, session, server, roots)
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	setProgram(p)
+	defer setProgram(nil)
 	finalModel, err := p.Run()
 	if err != nil {
 		return fmt.Errorf("error running program: %w", err)
@@ -801,6 +1434,7 @@ func handleSession(ctx context.Context, session *mcp.ClientSession) error {
 	appModel, ok := finalModel.(*AppModel)
 	if !ok {
 		return fmt.Errorf("unexpected model type: %T", finalModel)
+	}
 
 	return appModel.err
 }
@@ -809,5 +1443,10 @@ func main() {
 	rootCmd.AddCommand(stdioCmd)
 	rootCmd.AddCommand(sseCmd)
 	rootCmd.AddCommand(httpCmd)
+	rootCmd.AddCommand(connectCmd)
+	rootCmd.AddCommand(callCmd)
+	rootCmd.AddCommand(listCmd)
+	rootCmd.AddCommand(readCmd)
+	rootCmd.AddCommand(runCmd)
 	Execute()
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// The client handlers are registered before the TUI starts, so they reach it
+// through the running program. Messages sent while no program is running, as
+// in the headless commands, are dropped.
+var (
+	programMu sync.Mutex
+	program   *tea.Program
+)
+
+func setProgram(p *tea.Program) {
+	programMu.Lock()
+	defer programMu.Unlock()
+	program = p
+}
+
+// sendToProgram delivers msg to the running TUI, if there is one, and
+// reports whether it was delivered.
+func sendToProgram(msg tea.Msg) bool {
+	programMu.Lock()
+	p := program
+	programMu.Unlock()
+	if p == nil {
+		return false
+	}
+	p.Send(msg)
+	return true
+}
+
+// resourceUpdated is sent when the server reports a change to a subscribed resource.
+type resourceUpdated struct {
+	uri string
+}
+
+// clientOptions returns the handlers for server-initiated messages.
+func clientOptions() *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		CreateMessageHandler: handleCreateMessage,
+		ElicitationHandler:   handleElicit,
+		ResourceUpdatedHandler: func(ctx context.Context, req *mcp.ResourceUpdatedNotificationRequest) {
+			sendToProgram(resourceUpdated{uri: req.Params.URI})
+		},
+		LoggingMessageHandler: func(ctx context.Context, req *mcp.LoggingMessageRequest) {
+			if !sendToProgram(serverLog{params: req.Params}) {
+				log.Printf("Server log: %s", formatServerLog(req.Params))
+			}
+		},
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			sendToProgram(progressUpdate{params: req.Params})
+		},
+		ToolListChangedHandler:     listChangedHandler[*mcp.ToolListChangedRequest]("tools"),
+		PromptListChangedHandler:   listChangedHandler[*mcp.PromptListChangedRequest]("prompts"),
+		ResourceListChangedHandler: listChangedHandler[*mcp.ResourceListChangedRequest]("resources"),
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressUpdate is sent when the server reports progress on a request.
+type progressUpdate struct {
+	params *mcp.ProgressNotificationParams
+}
+
+// progressTick re-renders the elapsed time while a call is in flight.
+type progressTick struct{}
+
+// callProgress tracks the tool call that is currently in flight.
+type callProgress struct {
+	token    string
+	started  time.Time
+	progress float64
+	total    float64
+	message  string
+	bar      progress.Model
+}
+
+// startCall records a new in-flight call and returns the progress token to
+// attach to its request.
+func (m *AppModel) startCall() string {
+	m.callSeq++
+	m.inFlight = &callProgress{
+		token:   fmt.Sprintf("mcp-cli-%d", m.callSeq),
+		started: time.Now(),
+		bar:     progress.New(progress.WithDefaultGradient()),
+	}
+	return m.inFlight.token
+}
+
+// updateProgress applies a progress notification if it belongs to the
+// in-flight call.
+func (m *AppModel) updateProgress(msg progressUpdate) {
+	if m.inFlight == nil || fmt.Sprint(msg.params.ProgressToken) != m.inFlight.token {
+		return
+	}
+	m.inFlight.progress = msg.params.Progress
+	m.inFlight.total = msg.params.Total
+	m.inFlight.message = msg.params.Message
+	if verbose {
+		m.logf("Progress: %v/%v %s", msg.params.Progress, msg.params.Total, msg.params.Message)
+	}
+}
+
+func progressTickCmd() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(time.Time) tea.Msg {
+		return progressTick{}
+	})
+}
+
+// progressView renders the in-flight call's progress bar, server message and
+// elapsed time. Without a total from the server only the raw progress is shown.
+func (m AppModel) progressView(width int) string {
+	p := m.inFlight
+	elapsed := time.Since(p.started).Truncate(100 * time.Millisecond)
+	lines := []string{fmt.Sprintf("Running... %s", elapsed)}
+	if p.total > 0 {
+		p.bar.Width = min(width, 60)
+		lines = append(lines, p.bar.ViewAs(min(p.progress/p.total, 1)))
+	} else if p.progress > 0 {
+		lines = append(lines, fmt.Sprintf("Progress: %v", p.progress))
+	}
+	if p.message != "" {
+		lines = append(lines, p.message)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	userRoleStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))  // Blue
+	assistantRoleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("170")) // Magenta
+)
+
+func (m *AppModel) updatePromptArgumentInputView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		if m.promptArgFocus == len(m.promptArgInputs)-1 {
+			for i, arg := range m.selectedPrompt.Arguments {
+				if arg.Required && m.promptArgInputs[i].Value() == "" {
+					m.logf("Argument '%s' is required", arg.Name)
+					m.promptArgFocus = i
+					focusInput(m.promptArgInputs, m.promptArgFocus)
+					return m, nil
+				}
+			}
+			return m.getPrompt()
+		}
+		m.promptArgFocus++
+		focusInput(m.promptArgInputs, m.promptArgFocus)
+		return m, nil
+
+	case tea.KeyTab:
+		m.promptArgFocus = (m.promptArgFocus + 1) % len(m.promptArgInputs)
+		focusInput(m.promptArgInputs, m.promptArgFocus)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.promptArgInputs[m.promptArgFocus], cmd = m.promptArgInputs[m.promptArgFocus].Update(msg)
+	return m, cmd
+}
+
+func (m *AppModel) getPrompt() (tea.Model, tea.Cmd) {
+	if verbose {
+		m.logf("State change: -> promptDetailView")
+	}
+	m.state = promptDetailView
+	m.promptResult = ""
+	return m, m.getPromptCmd()
+}
+
+// getPromptCmd returns a tea.Cmd that gets the selected prompt with the
+// arguments entered in the form.
+func (m *AppModel) getPromptCmd() tea.Cmd {
+	params := &mcp.GetPromptParams{
+		Name:      m.selectedPrompt.Name,
+		Arguments: make(map[string]string),
+	}
+	for i, arg := range m.selectedPrompt.Arguments {
+		if value := m.promptArgInputs[i].Value(); value != "" {
+			params.Arguments[arg.Name] = value
+		}
+	}
+	m.logf("========\nGetting prompt '%s' with args: %v", params.Name, params.Arguments)
+
+	session := m.session
+	ctx, cancel := m.startRequest("prompt")
+	return func() tea.Msg {
+		defer cancel()
+		result, err := session.GetPrompt(ctx, params)
+		if status := requestStatus(err); status != "" {
+			return promptResult{result: status}
+		}
+		if err != nil {
+			return promptResult{err: err}
+		}
+		return promptResult{result: formatPromptMessages(result)}
+	}
+}
+
+// formatPromptMessages renders a prompt result as a transcript, with each
+// message labelled by its role.
+func formatPromptMessages(result *mcp.GetPromptResult) string {
+	var b strings.Builder
+	if result.Description != "" {
+		b.WriteString(result.Description)
+		b.WriteString("\n\n")
+	}
+	for i, msg := range result.Messages {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		style := userRoleStyle
+		if msg.Role == "assistant" {
+			style = assistantRoleStyle
+		}
+		b.WriteString(style.Render(strings.ToUpper(string(msg.Role))))
+		b.WriteString("\n")
+		b.WriteString(formatContent(msg.Content))
+	}
+	return b.String()
+}
+
+// formatContent renders a single content block as text. Binary data is
+// summarised rather than printed.
+func formatContent(content mcp.Content) string {
+	switch c := content.(type) {
+	case *mcp.TextContent:
+		return c.Text
+	case *mcp.EmbeddedResource:
+		if c.Resource != nil && c.Resource.Blob == nil {
+			return describeContent(c) + "\n" + c.Resource.Text
+		}
+	}
+	return describeContent(content)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var readCmd = &cobra.Command{
+	Use:   "read [transport] [target] [uri...]",
+	Short: "Read one or more resources without the TUI",
+	Long: `Read resources and write their contents to stdout or to disk.
+
+Text contents are written to stdout as is. Binary (blob) contents must be saved
+with -o. If more than one content is returned, -o must name a directory, and
+each content is saved under the last path segment of its URI.`,
+	Example: `  mcp-cli read stdio "python server.py" file:///notes.txt
+  mcp-cli read http http://localhost:8080/mcp file:///logo.png -o logo.png
+  mcp-cli read http http://localhost:8080/mcp file:///a.txt file:///b.bin -o out/`,
+	Args: cobra.MinimumNArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, target, uris := args[0], args[1], args[2:]
+		output, _ := cmd.Flags().GetString("output")
+
+		ctx := context.Background()
+		session, err := connectHeadless(ctx, cmd, kind, target)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s server: %v", kind, err)
+		}
+
+		var contents []*mcp.ResourceContents
+		for _, uri := range uris {
+			reqCtx, cancel := newRequestContext(ctx)
+			result, err := session.ReadResource(reqCtx, &mcp.ReadResourceParams{URI: uri})
+			cancel()
+			if err != nil {
+				session.Close()
+				log.Fatalf("Failed to read resource '%s': %v", uri, err)
+			}
+			contents = append(contents, result.Contents...)
+		}
+		session.Close()
+
+		if err := writeResourceContents(contents, output); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// writeResourceContents writes text contents to stdout when output is empty,
+// and otherwise saves every content to the file or directory named by output.
+func writeResourceContents(contents []*mcp.ResourceContents, output string) error {
+	if output == "" {
+		for _, c := range contents {
+			if c.Blob != nil {
+				return fmt.Errorf("resource '%s' has binary content; use -o to save it", c.URI)
+			}
+			fmt.Print(c.Text)
+		}
+		return nil
+	}
+
+	info, err := os.Stat(output)
+	isDir := err == nil && info.IsDir()
+	if !isDir && len(contents) > 1 {
+		return fmt.Errorf("%d contents returned; -o must name an existing directory", len(contents))
+	}
+
+	used := map[string]bool{}
+	for _, c := range contents {
+		data := c.Blob
+		if data == nil {
+			data = []byte(c.Text)
+		}
+		dest := output
+		if isDir {
+			name := uniqueFileName(contentFileName(c.URI), used)
+			if name != contentFileName(c.URI) {
+				log.Printf("Saving %s as %s, since another content has the same name", c.URI, name)
+			}
+			dest = filepath.Join(output, name)
+		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return fmt.Errorf("failed to save resource '%s': %w", c.URI, err)
+		}
+		if verbose {
+			log.Printf("Wrote %d bytes from %s to %s", len(data), c.URI, dest)
+		}
+	}
+	return nil
+}
+
+// uniqueFileName returns name, or name with a -1, -2, ... suffix before its
+// extension if it is already in used, and adds the result to used.
+func uniqueFileName(name string, used map[string]bool) string {
+	unique := name
+	ext := path.Ext(name)
+	for n := 1; used[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), n, ext)
+	}
+	used[unique] = true
+	return unique
+}
+
+// contentFileName derives a file name from the last path segment of uri.
+func contentFileName(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "resource"
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		name = u.Host
+	}
+	if name == "" {
+		return "resource"
+	}
+	return name
+}
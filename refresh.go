@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// listChanged is sent when the server reports that its tools, prompts or
+// resources changed. kind is "tools", "prompts" or "resources".
+type listChanged struct {
+	kind string
+}
+
+// listRefreshed carries the re-fetched items for a listChanged notification.
+type listRefreshed struct {
+	kind      string
+	tools     []*mcp.Tool
+	prompts   []*mcp.Prompt
+	resources []*mcp.Resource
+	err       error
+}
+
+// refreshListCmd returns a tea.Cmd that lists the items of the given kind again.
+func (m *AppModel) refreshListCmd(kind string) tea.Cmd {
+	session, ctx := m.session, m.ctx
+	return func() tea.Msg {
+		msg := listRefreshed{kind: kind}
+		switch kind {
+		case "tools":
+			msg.tools, msg.err = collect(session.Tools(ctx, nil))
+		case "prompts":
+			msg.prompts, msg.err = collect(session.Prompts(ctx, nil))
+		case "resources":
+			msg.resources, msg.err = collect(session.Resources(ctx, nil))
+		}
+		return msg
+	}
+}
+
+// applyRefresh replaces the list for msg.kind with the re-fetched items and
+// logs which entries were added or removed.
+func (m *AppModel) applyRefresh(msg listRefreshed) tea.Cmd {
+	if msg.err != nil {
+		m.logf("Failed to refresh %s: %v", msg.kind, msg.err)
+		return nil
+	}
+
+	var target *list.Model
+	items := []list.Item{}
+	switch msg.kind {
+	case "tools":
+		m.tools = msg.tools
+		target = &m.toolList
+		for _, tool := range msg.tools {
+			items = append(items, item{title: tool.Name, desc: tool.Description, tool: tool})
+		}
+	case "prompts":
+		m.prompts = msg.prompts
+		target = &m.promptList
+		for _, prompt := range msg.prompts {
+			items = append(items, promptItem{title: prompt.Name, desc: prompt.Description, prompt: prompt})
+		}
+	case "resources":
+		m.resources = msg.resources
+		target = &m.resourceList
+		for _, resource := range msg.resources {
+			items = append(items, resourceItem{title: resource.Name, desc: resource.Description, resource: resource})
+		}
+	default:
+		return nil
+	}
+
+	added, removed, cmd := replaceItems(target, items)
+	label := strings.ToUpper(msg.kind[:1]) + msg.kind[1:]
+	if len(added) > 0 {
+		m.logf("%s added: %s", label, strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		m.logf("%s removed: %s", label, strings.Join(removed, ", "))
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		m.logf("%s list changed", label)
+	}
+	return cmd
+}
+
+// replaceItems swaps the items of l, keeping the cursor on the previously
+// selected entry if it still exists. It returns the titles of the entries
+// that were added and removed.
+func replaceItems(l *list.Model, items []list.Item) (added, removed []string, cmd tea.Cmd) {
+	title := func(i list.Item) string { return i.(list.DefaultItem).Title() }
+
+	var selected string
+	if sel := l.SelectedItem(); sel != nil {
+		selected = title(sel)
+	}
+
+	var oldTitles, newTitles []string
+	for _, i := range l.Items() {
+		oldTitles = append(oldTitles, title(i))
+	}
+	for _, i := range items {
+		newTitles = append(newTitles, title(i))
+	}
+	for _, t := range newTitles {
+		if !slices.Contains(oldTitles, t) {
+			added = append(added, t)
+		}
+	}
+	for _, t := range oldTitles {
+		if !slices.Contains(newTitles, t) {
+			removed = append(removed, t)
+		}
+	}
+
+	cmd = l.SetItems(items)
+	if idx := slices.Index(newTitles, selected); idx >= 0 {
+		l.Select(idx)
+	}
+	return added, removed, cmd
+}
+
+// listChangedHandler returns a client notification handler that asks the TUI
+// to refresh the list of the given kind.
+func listChangedHandler[R any](kind string) func(context.Context, R) {
+	return func(context.Context, R) {
+		sendToProgram(listChanged{kind: kind})
+	}
+}
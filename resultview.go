@@ -0,0 +1,361 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	searchMatchStyle   = lipgloss.NewStyle().Background(lipgloss.Color("58"))                                  // Olive
+	searchCurrentStyle = lipgloss.NewStyle().Background(lipgloss.Color("205")).Foreground(lipgloss.Color("0")) // Pink
+)
+
+// resultScrollStep is the number of columns scrolled by left and right when
+// lines are not wrapped.
+const resultScrollStep = 8
+
+// resultPrompt is the question the result view asks below the result.
+type resultPrompt int
+
+const (
+	noPrompt resultPrompt = iota
+	searchPrompt
+	savePrompt
+)
+
+// resultPane is the scroll, wrap and search state of the tool result view.
+type resultPane struct {
+	yOffset  int
+	xOffset  int
+	noWrap   bool
+	prompt   resultPrompt
+	input    textinput.Model
+	query    string
+	match    int // index of the current match among the matching lines
+	selected int // index of the selected attachment
+}
+
+// reset scrolls back to the top and clears the search for a new result. The
+// wrap setting is kept.
+func (p *resultPane) reset() {
+	*p = resultPane{noWrap: p.noWrap}
+}
+
+// matches returns the indexes of the lines that contain the query, ignoring
+// case.
+func (p *resultPane) matches(lines []string) []int {
+	if p.query == "" {
+		return nil
+	}
+	query := strings.ToLower(p.query)
+	var indexes []int
+	for i, line := range lines {
+		if strings.Contains(strings.ToLower(ansi.Strip(line)), query) {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// resultPaneSize returns the size of the scrolling part of the result view,
+// leaving room for the heading, status and help lines.
+func (m *AppModel) resultPaneSize() (width, height int) {
+	width, height = m.mainPanelSize()
+	height -= 5
+	if len(m.attachments()) > 0 {
+		height--
+	}
+	return width - 4, max(height, 1)
+}
+
+// resultLines renders the result for the pane, soft-wrapped to width unless
+// wrapping is turned off.
+func (m *AppModel) resultLines(width int) []string {
+	if m.resultPane.noWrap {
+		width = 0
+	}
+	contentWidth := width
+	if m.resultStructured.shown() {
+		contentWidth = (width - 2) / 2
+	}
+	content := m.resultContentView(contentWidth)
+	return strings.Split(renderToolResult(content, m.resultStructured, width), "\n")
+}
+
+func (m *AppModel) updateToolResultView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	p := &m.resultPane
+	keyMsg, ok := msg.(tea.KeyMsg)
+
+	if p.prompt != noPrompt {
+		if ok {
+			switch keyMsg.Type {
+			case tea.KeyEnter:
+				prompt := p.prompt
+				p.prompt = noPrompt
+				if prompt == savePrompt {
+					m.saveAttachment(p.input.Value())
+					return m, nil
+				}
+				p.query = p.input.Value()
+				p.match = 0
+				m.showMatch()
+				return m, nil
+			case tea.KeyEsc:
+				p.prompt = noPrompt
+				return m, nil
+			}
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return m, cmd
+	}
+	if !ok {
+		return m, nil
+	}
+
+	width, height := m.resultPaneSize()
+	switch keyMsg.String() {
+	case "up", "k":
+		p.yOffset--
+	case "down", "j":
+		p.yOffset++
+	case "pgup", "b":
+		p.yOffset -= height
+	case "pgdown", "f", " ":
+		p.yOffset += height
+	case "home", "g":
+		p.yOffset = 0
+	case "end", "G":
+		p.yOffset = len(m.resultLines(width))
+	case "left", "h":
+		p.xOffset -= resultScrollStep
+	case "right", "l":
+		if p.noWrap {
+			p.xOffset += resultScrollStep
+		}
+	case "w":
+		p.noWrap = !p.noWrap
+		p.xOffset = 0
+	case "/":
+		return m, p.ask(searchPrompt, "/", p.query)
+	case "]":
+		m.selectAttachment(p.selected + 1)
+	case "[":
+		m.selectAttachment(p.selected - 1)
+	case "enter":
+		return m, m.openAttachment()
+	case "s":
+		attachments := m.attachments()
+		if p.selected >= len(attachments) {
+			return m, nil
+		}
+		if attachments[p.selected].data() == nil {
+			m.logf("Only images, audio and embedded resources can be saved")
+			return m, nil
+		}
+		return m, p.ask(savePrompt, "Save to: ", attachments[p.selected].fileName(m.resultTool, p.selected+1))
+	case "n":
+		p.match++
+		m.showMatch()
+	case "N":
+		p.match--
+		m.showMatch()
+	case "e", "r":
+		if !m.useHistoryEntry(&historyEntry{Tool: m.resultTool, Arguments: m.resultArgs}) {
+			return m, nil
+		}
+		if keyMsg.String() == "r" {
+			return m.callTool(m.resultArgs)
+		}
+		if m.argForm == nil {
+			m.logf("%s takes no arguments", m.resultTool)
+			return m, nil
+		}
+		m.state = argumentInputView
+		return m, nil
+	}
+	m.clampResultPane()
+	return m, nil
+}
+
+// ask shows a prompt with an initial value below the result.
+func (p *resultPane) ask(prompt resultPrompt, label, value string) tea.Cmd {
+	p.prompt = prompt
+	p.input = textinput.New()
+	p.input.Prompt = label
+	p.input.SetValue(value)
+	return p.input.Focus()
+}
+
+// selectAttachment selects the attachment at index i, wrapping around at
+// either end, and scrolls it into view.
+func (m *AppModel) selectAttachment(i int) {
+	n := len(m.attachments())
+	if n == 0 {
+		return
+	}
+	p := &m.resultPane
+	p.selected = (i%n + n) % n
+
+	width, height := m.resultPaneSize()
+	marker := fmt.Sprintf("> %d. ", p.selected+1)
+	for line, text := range m.resultLines(width) {
+		if strings.Contains(ansi.Strip(text), marker) {
+			if line < p.yOffset || line >= p.yOffset+height {
+				p.yOffset = line - height/3
+			}
+			break
+		}
+	}
+}
+
+// saveAttachment writes the selected attachment to path.
+func (m *AppModel) saveAttachment(path string) {
+	attachments := m.attachments()
+	if path == "" || m.resultPane.selected >= len(attachments) {
+		return
+	}
+	if err := attachments[m.resultPane.selected].save(path); err != nil {
+		m.logf("Failed to save attachment: %v", err)
+		return
+	}
+	m.logf("Saved attachment to %s", path)
+}
+
+// clampResultPane keeps the scroll offsets within the rendered result.
+func (m *AppModel) clampResultPane() {
+	width, height := m.resultPaneSize()
+	lines := m.resultLines(width)
+	p := &m.resultPane
+	p.yOffset = max(min(p.yOffset, len(lines)-height), 0)
+
+	longest := 0
+	for _, line := range lines {
+		longest = max(longest, ansi.StringWidth(line))
+	}
+	p.xOffset = max(min(p.xOffset, longest-width), 0)
+}
+
+// showMatch scrolls the pane to the current search match, wrapping around at
+// either end.
+func (m *AppModel) showMatch() {
+	width, height := m.resultPaneSize()
+	lines := m.resultLines(width)
+	p := &m.resultPane
+	matches := p.matches(lines)
+	if len(matches) == 0 {
+		if p.query != "" {
+			m.logf("No matches for %q", p.query)
+		}
+		return
+	}
+	p.match = (p.match%len(matches) + len(matches)) % len(matches)
+	line := matches[p.match]
+	p.yOffset = line - height/3
+
+	if p.noWrap {
+		plain := strings.ToLower(ansi.Strip(lines[line]))
+		col := utf8.RuneCountInString(plain[:strings.Index(plain, strings.ToLower(p.query))])
+		if col < p.xOffset || col >= p.xOffset+width {
+			p.xOffset = col - width/3
+		}
+	}
+	m.clampResultPane()
+}
+
+// resultPaneView renders the visible part of the result with the status and
+// help lines below it.
+func (m *AppModel) resultPaneView() string {
+	width, height := m.resultPaneSize()
+	lines := m.resultLines(width)
+	p := m.resultPane
+	matches := p.matches(lines)
+	current := -1
+	if p.match >= 0 && p.match < len(matches) {
+		current = matches[p.match]
+	}
+
+	start := max(min(p.yOffset, len(lines)-height), 0)
+	end := min(start+height, len(lines))
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if p.noWrap && p.xOffset > 0 {
+			line = cutLeft(ansi.Strip(line), p.xOffset)
+		}
+		if p.query != "" && strings.Contains(strings.ToLower(ansi.Strip(line)), strings.ToLower(p.query)) {
+			style := searchMatchStyle
+			if i == current {
+				style = searchCurrentStyle
+			}
+			line = highlight(ansi.Strip(line), p.query, style)
+		}
+		if p.noWrap {
+			line = ansi.Truncate(line, width, "")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	for i := end - start; i < height; i++ {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if p.prompt != noPrompt {
+		b.WriteString(p.input.View())
+	} else {
+		status := fmt.Sprintf("Lines %d-%d of %d", min(start+1, end), end, len(lines))
+		if p.noWrap {
+			status += fmt.Sprintf(", column %d", p.xOffset+1)
+		}
+		if p.query != "" {
+			if len(matches) == 0 {
+				status += fmt.Sprintf(", no matches for %q", p.query)
+			} else {
+				status += fmt.Sprintf(", match %d of %d for %q", p.match+1, len(matches), p.query)
+			}
+		}
+		b.WriteString(formHintStyle.Render(status))
+	}
+	b.WriteString("\nw wrap, / search, n/N next/previous match, e edit arguments, r re-run, Esc back.")
+	if len(m.attachments()) > 0 {
+		b.WriteString("\n[/] select attachment, Enter open, s save.")
+	}
+	return b.String()
+}
+
+// cutLeft drops the first n characters of the plain text s.
+func cutLeft(s string, n int) string {
+	runes := []rune(s)
+	if n >= len(runes) {
+		return ""
+	}
+	return string(runes[n:])
+}
+
+// highlight renders each occurrence of query in the plain text line with
+// style, ignoring case where that does not change the length of the line.
+func highlight(line, query string, style lipgloss.Style) string {
+	lower, q := strings.ToLower(line), strings.ToLower(query)
+	if len(lower) != len(line) || len(q) != len(query) {
+		lower, q = line, query
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, q)
+		if i < 0 {
+			break
+		}
+		b.WriteString(line[:i])
+		b.WriteString(style.Render(line[i : i+len(q)]))
+		line, lower = line[i+len(q):], lower[i+len(q):]
+	}
+	b.WriteString(line)
+	return b.String()
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+// clientRoots keeps the roots registered on a client. The client does not
+// expose its roots, so the TUI lists them from here. It outlives a single
+// session, so roots edited in the TUI survive a reconnect.
+type clientRoots struct {
+	client *mcp.Client
+	roots  []*mcp.Root
+}
+
+func newClientRoots(client *mcp.Client, roots []*mcp.Root) *clientRoots {
+	return &clientRoots{client: client, roots: roots}
+}
+
+// add registers root, replacing any root with the same URI. Connected
+// servers are sent notifications/roots/list_changed.
+func (r *clientRoots) add(root *mcp.Root) {
+	r.remove(root.URI)
+	r.roots = append(r.roots, root)
+	r.client.AddRoots(root)
+}
+
+// remove unregisters the root with the given URI.
+func (r *clientRoots) remove(uri string) {
+	for i, root := range r.roots {
+		if root.URI == uri {
+			r.roots = append(r.roots[:i], r.roots[i+1:]...)
+			r.client.RemoveRoots(uri)
+			return
+		}
+	}
+}
+
+// addRootFlag registers the repeatable --root flag on cmd.
+func addRootFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("root", []string{}, "Directory to expose to the server as a file:// root, as path[:name] (repeatable)")
+}
+
+// rootsFromFlags parses the --root flags registered on cmd.
+func rootsFromFlags(cmd *cobra.Command) ([]*mcp.Root, error) {
+	rootStrings, _ := cmd.Flags().GetStringArray("root")
+	var roots []*mcp.Root
+	for _, s := range rootStrings {
+		root, err := parseRoot(s)
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// parseRoot turns "path[:name]" into a file:// root. The path must exist, and
+// the name defaults to the last element of the path.
+func parseRoot(s string) (*mcp.Root, error) {
+	path, name := s, ""
+	if i := strings.LastIndex(s, ":"); i > 0 && !strings.ContainsAny(s[i+1:], `/\`) {
+		path, name = s[:i], s[i+1:]
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid root %q: %w", s, err)
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return nil, fmt.Errorf("invalid root %q: %w", s, err)
+	}
+	if name == "" {
+		name = filepath.Base(abs)
+	}
+
+	uriPath := filepath.ToSlash(abs)
+	if !strings.HasPrefix(uriPath, "/") {
+		uriPath = "/" + uriPath // Windows drive letters
+	}
+	uri := &url.URL{Scheme: "file", Path: uriPath}
+	return &mcp.Root{URI: uri.String(), Name: name}, nil
+}
+
+type rootItem struct {
+	root *mcp.Root
+}
+
+func (i rootItem) Title() string       { return i.root.Name }
+func (i rootItem) Description() string { return i.root.URI }
+func (i rootItem) FilterValue() string { return i.root.Name }
+
+func newRootsList(roots *clientRoots) list.Model {
+	l := list.New(rootItems(roots), list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Roots shared with the server"
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{
+			key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "add root")),
+			key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "remove root")),
+			key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tools")),
+		}
+	}
+	return l
+}
+
+func rootItems(roots *clientRoots) []list.Item {
+	items := []list.Item{}
+	if roots == nil {
+		return items
+	}
+	for _, root := range roots.roots {
+		items = append(items, rootItem{root: root})
+	}
+	return items
+}
+
+func (m *AppModel) updateRootsView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.addingRoot {
+		return m.updateRootInput(msg)
+	}
+
+	// Handle our keys before the list sees them, since "d" also pages the list.
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || m.rootsList.FilterState() == list.Filtering {
+		var cmd tea.Cmd
+		m.rootsList, cmd = m.rootsList.Update(msg)
+		return m, cmd
+	}
+	switch keyMsg.String() {
+	case "t":
+		m.state = toolSelectionView
+		return m, nil
+	case "a":
+		m.addingRoot = true
+		m.rootInput = textinput.New()
+		m.rootInput.Placeholder = "path[:name]"
+		m.rootInput.CharLimit = 1024
+		m.rootInput.Width = 50
+		return m, m.rootInput.Focus()
+	case "d":
+		selected, ok := m.rootsList.SelectedItem().(rootItem)
+		if !ok {
+			return m, nil
+		}
+		m.roots.remove(selected.root.URI)
+		m.logf("Removed root %s", selected.root.URI)
+		return m, m.rootsList.SetItems(rootItems(m.roots))
+	}
+
+	var cmd tea.Cmd
+	m.rootsList, cmd = m.rootsList.Update(msg)
+	return m, cmd
+}
+
+func (m *AppModel) updateRootInput(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEsc:
+			m.addingRoot = false
+			return m, nil
+		case tea.KeyEnter:
+			root, err := parseRoot(m.rootInput.Value())
+			if err != nil {
+				m.logf("%v", err)
+				return m, nil
+			}
+			m.roots.add(root)
+			m.logf("Added root %s (%s)", root.URI, root.Name)
+			m.addingRoot = false
+			return m, m.rootsList.SetItems(rootItems(m.roots))
+		}
+	}
+
+	var cmd tea.Cmd
+	m.rootInput, cmd = m.rootInput.Update(msg)
+	return m, cmd
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run [transport] [target] [collection]",
+	Short: "Run the requests in a collection file without the TUI",
+	Long: `Run the tool calls saved in a collection file, in order, and print each result.
+
+A collection is a YAML or JSON file with a list of named tool calls, in the same
+format as the presets saved in the TUI. String arguments may refer to variables
+as {{name}}; values come from the collection's variables section and can be
+overridden with --var, so one collection can be run against several
+environments. A value that is only a reference, such as "{{count}}", takes the
+type of the argument in the tool's input schema. The command exits with a non-zero status if any request fails or
+the tool returns an error result.`,
+	Example: `  mcp-cli run stdio "python server.py" smoke.yaml
+  mcp-cli run http https://staging.example.com/mcp smoke.yaml --var user=alice --request "add small"`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		kind, target, path := args[0], args[1], args[2]
+		names, _ := cmd.Flags().GetStringArray("request")
+		varStrings, _ := cmd.Flags().GetStringArray("var")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+
+		vars, err := parseVars(varStrings)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			log.Fatalf("Failed to load collection: %v", err)
+		}
+		c, err := loadCollection(path)
+		if err != nil {
+			log.Fatalf("Failed to load collection: %v", err)
+		}
+		requests, err := selectRequests(c, names)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		ctx := context.Background()
+		session, err := connectHeadless(ctx, cmd, kind, target)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s server: %v", kind, err)
+		}
+
+		failed := 0
+		for i, r := range requests {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("=== %s (%s)\n", r.Name, r.Tool)
+			if err := runRequest(ctx, session, c, r, vars); err != nil {
+				log.Printf("Request '%s' failed: %v", r.Name, err)
+				failed++
+				if failFast {
+					break
+				}
+			}
+		}
+		session.Close()
+
+		if failed > 0 {
+			log.Printf("%d of %d requests failed", failed, len(requests))
+			os.Exit(1)
+		}
+	},
+}
+
+// parseVars parses --var flags of the form name=value.
+func parseVars(varStrings []string) (map[string]string, error) {
+	vars := map[string]string{}
+	for _, v := range varStrings {
+		name, value, ok := strings.Cut(v, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q, expected name=value", v)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+// selectRequests returns the requests of c with the given names, or all of
+// them if no names are given.
+func selectRequests(c *collection, names []string) ([]*savedRequest, error) {
+	if len(c.Requests) == 0 {
+		return nil, fmt.Errorf("no requests in %s", c.path)
+	}
+	if len(names) == 0 {
+		return c.Requests, nil
+	}
+	var requests []*savedRequest
+	for _, name := range names {
+		i := slices.IndexFunc(c.Requests, func(r *savedRequest) bool { return r.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("no request named %q in %s", name, c.path)
+		}
+		requests = append(requests, c.Requests[i])
+	}
+	return requests, nil
+}
+
+// runRequest calls the tool of a saved request and prints the result.
+func runRequest(ctx context.Context, session *mcp.ClientSession, c *collection, r *savedRequest, vars map[string]string) error {
+	reqCtx, cancel := newRequestContext(ctx)
+	defer cancel()
+	tool, err := findTool(reqCtx, session, r.Tool)
+	if err != nil {
+		return err
+	}
+	args, err := c.arguments(r, vars, tool.InputSchema)
+	if err != nil {
+		return err
+	}
+	result, err := callToolArgs(reqCtx, session, tool, args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(formatToolResult(result))
+	structured := checkStructuredContent(tool, result)
+	if verbose || len(structured.violations) > 0 || len(structured.warnings) > 0 {
+		for _, line := range structured.summary() {
+			log.Print(line)
+		}
+	}
+	if result.IsError {
+		return errors.New("the tool returned an error result")
+	}
+	return nil
+}
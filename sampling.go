@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// operatorModel is the model name reported for replies typed in the TUI.
+const operatorModel = "mcp-cli-operator"
+
+var errSamplingRejected = errors.New("sampling request rejected by the user")
+
+// samplingRequest is sent when the server calls sampling/createMessage. The
+// handler waits for the operator's answer on reply.
+type samplingRequest struct {
+	params *mcp.CreateMessageParams
+	reply  chan samplingReply
+}
+
+type samplingReply struct {
+	result *mcp.CreateMessageResult
+	err    error
+}
+
+// samplingAbandoned is sent when the server stops waiting for a sampling
+// request, for example because the tool call that issued it was cancelled.
+type samplingAbandoned struct {
+	reply chan samplingReply
+}
+
+// handleCreateMessage forwards a sampling request to --sampling-endpoint, or
+// otherwise shows it to the operator and waits for them to answer or reject it.
+func handleCreateMessage(ctx context.Context, req *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+	if samplingEndpoint != "" {
+		return forwardCreateMessage(ctx, req.Params)
+	}
+	reply := make(chan samplingReply, 1)
+	if !sendToProgram(samplingRequest{params: req.Params, reply: reply}) {
+		return nil, errors.New("sampling is only supported in the interactive TUI")
+	}
+	select {
+	case r := <-reply:
+		return r.result, r.err
+	case <-ctx.Done():
+		sendToProgram(samplingAbandoned{reply: reply})
+		return nil, ctx.Err()
+	}
+}
+
+// queueSampling adds a sampling request and opens the sampling view if it
+// is not already showing one.
+func (m *AppModel) queueSampling(req samplingRequest) tea.Cmd {
+	m.samplingQueue = append(m.samplingQueue, req)
+	m.logf("Server requested sampling (%d pending)", len(m.samplingQueue))
+	if len(m.samplingQueue) > 1 {
+		return nil
+	}
+	m.samplingReturnState = m.state
+	m.state = samplingView
+	return m.resetSamplingInput()
+}
+
+// dropSampling removes the request answered through reply from the queue and
+// moves on to the next one, or back to the previous view.
+func (m *AppModel) dropSampling(reply chan samplingReply) tea.Cmd {
+	for i, req := range m.samplingQueue {
+		if req.reply != reply {
+			continue
+		}
+		m.samplingQueue = append(m.samplingQueue[:i], m.samplingQueue[i+1:]...)
+		if i != 0 {
+			return nil
+		}
+		if len(m.samplingQueue) > 0 {
+			return m.resetSamplingInput()
+		}
+		if m.state == samplingView {
+			m.state = m.samplingReturnState
+		}
+		return nil
+	}
+	return nil
+}
+
+func (m *AppModel) resetSamplingInput() tea.Cmd {
+	ta := textarea.New()
+	ta.Placeholder = "Type the assistant reply..."
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.SetHeight(6)
+	m.samplingInput = ta
+	return m.samplingInput.Focus()
+}
+
+func (m *AppModel) updateSamplingView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if len(m.samplingQueue) == 0 {
+		m.state = m.samplingReturnState
+		return m, nil
+	}
+	req := m.samplingQueue[0]
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "ctrl+s":
+			text := m.samplingInput.Value()
+			m.logf("========\nSampling reply sent:\n%s", text)
+			req.reply <- samplingReply{result: &mcp.CreateMessageResult{
+				Content:    &mcp.TextContent{Text: text},
+				Model:      operatorModel,
+				Role:       "assistant",
+				StopReason: "endTurn",
+			}}
+			return m, m.dropSampling(req.reply)
+		case "ctrl+r":
+			m.logf("Sampling request rejected")
+			req.reply <- samplingReply{err: errSamplingRejected}
+			return m, m.dropSampling(req.reply)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.samplingInput, cmd = m.samplingInput.Update(msg)
+	return m, cmd
+}
+
+// samplingRequestView renders the messages, system prompt and model
+// preferences of a sampling request.
+func samplingRequestView(p *mcp.CreateMessageParams) string {
+	var b strings.Builder
+	if p.SystemPrompt != "" {
+		b.WriteString(fmt.Sprintf("System prompt: %s\n", p.SystemPrompt))
+	}
+	if prefs := p.ModelPreferences; prefs != nil {
+		var hints []string
+		for _, h := range prefs.Hints {
+			hints = append(hints, h.Name)
+		}
+		b.WriteString(fmt.Sprintf("Model preferences: hints [%s], cost %.2f, speed %.2f, intelligence %.2f\n",
+			strings.Join(hints, ", "), prefs.CostPriority, prefs.SpeedPriority, prefs.IntelligencePriority))
+	}
+	b.WriteString(fmt.Sprintf("Max tokens: %d", p.MaxTokens))
+	if p.Temperature != 0 {
+		b.WriteString(fmt.Sprintf(", temperature: %.2f", p.Temperature))
+	}
+	if len(p.StopSequences) > 0 {
+		b.WriteString(fmt.Sprintf(", stop sequences: %q", p.StopSequences))
+	}
+	if p.IncludeContext != "" {
+		b.WriteString(fmt.Sprintf(", include context: %s", p.IncludeContext))
+	}
+	b.WriteString("\n")
+
+	for _, msg := range p.Messages {
+		style := userRoleStyle
+		if msg.Role == "assistant" {
+			style = assistantRoleStyle
+		}
+		b.WriteString("\n")
+		b.WriteString(style.Render(strings.ToUpper(string(msg.Role))))
+		b.WriteString("\n")
+		b.WriteString(formatContent(msg.Content))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
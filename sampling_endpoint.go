@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// samplingLog carries an audit entry for a forwarded sampling request.
+type samplingLog struct {
+	text string
+}
+
+// auditSampling writes an audit entry to the debug panel, or to the log when
+// no TUI is running.
+func auditSampling(format string, a ...any) {
+	text := fmt.Sprintf(format, a...)
+	if !sendToProgram(samplingLog{text: text}) {
+		log.Print(text)
+	}
+}
+
+// chatMessage is a message in an OpenAI chat completion request. Content is
+// either a string or a list of content parts.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content any    `json:"content"`
+}
+
+type chatContentPart struct {
+	Type     string        `json:"type"`
+	Text     string        `json:"text,omitempty"`
+	ImageURL *chatImageURL `json:"image_url,omitempty"`
+}
+
+type chatImageURL struct {
+	URL string `json:"url"`
+}
+
+type chatRequest struct {
+	Model       string        `json:"model"`
+	Messages    []chatMessage `json:"messages"`
+	MaxTokens   int64         `json:"max_tokens,omitempty"`
+	Temperature *float64      `json:"temperature,omitempty"`
+	Stop        []string      `json:"stop,omitempty"`
+}
+
+type chatResponse struct {
+	Model   string `json:"model"`
+	Choices []struct {
+		Message struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"message"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+// forwardCreateMessage sends a sampling request to the OpenAI-compatible
+// chat completions API at --sampling-endpoint and converts the reply.
+func forwardCreateMessage(ctx context.Context, params *mcp.CreateMessageParams) (*mcp.CreateMessageResult, error) {
+	chatReq, err := toChatRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(samplingEndpoint, "/") + "/chat/completions"
+	auditSampling("========\nForwarding sampling request to %s:\n%s", url, body)
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	// Only a key the user named for this purpose is sent, so that testing
+	// against a local or stand-in endpoint cannot leak another key.
+	if samplingKeyEnv != "" {
+		key := os.Getenv(samplingKeyEnv)
+		if key == "" {
+			return nil, fmt.Errorf("sampling endpoint: %s is not set", samplingKeyEnv)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+key)
+	}
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		auditSampling("Sampling endpoint request failed: %v", err)
+		return nil, fmt.Errorf("sampling endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("sampling endpoint: %w", err)
+	}
+	auditSampling("========\nSampling endpoint response (%s):\n%s", resp.Status, respBody)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sampling endpoint returned %s", resp.Status)
+	}
+
+	var chatResp chatResponse
+	if err := json.Unmarshal(respBody, &chatResp); err != nil {
+		return nil, fmt.Errorf("invalid sampling endpoint response: %w", err)
+	}
+	if len(chatResp.Choices) == 0 {
+		return nil, fmt.Errorf("sampling endpoint returned no choices")
+	}
+	choice := chatResp.Choices[0]
+	return &mcp.CreateMessageResult{
+		Content:    &mcp.TextContent{Text: choice.Message.Content},
+		Model:      chatResp.Model,
+		Role:       "assistant",
+		StopReason: stopReason(choice.FinishReason),
+	}, nil
+}
+
+// toChatRequest maps a sampling request onto a chat completion request. The
+// first model hint is used as the model name, falling back to --sampling-model.
+func toChatRequest(params *mcp.CreateMessageParams) (*chatRequest, error) {
+	req := &chatRequest{
+		Model:     samplingModel,
+		MaxTokens: params.MaxTokens,
+		Stop:      params.StopSequences,
+	}
+	if prefs := params.ModelPreferences; prefs != nil && len(prefs.Hints) > 0 && prefs.Hints[0].Name != "" {
+		req.Model = prefs.Hints[0].Name
+	}
+	if params.Temperature != 0 {
+		t := params.Temperature
+		req.Temperature = &t
+	}
+	if params.SystemPrompt != "" {
+		req.Messages = append(req.Messages, chatMessage{Role: "system", Content: params.SystemPrompt})
+	}
+	for _, msg := range params.Messages {
+		var content any
+		switch c := msg.Content.(type) {
+		case *mcp.TextContent:
+			content = c.Text
+		case *mcp.ImageContent:
+			content = []chatContentPart{{
+				Type:     "image_url",
+				ImageURL: &chatImageURL{URL: fmt.Sprintf("data:%s;base64,%s", c.MIMEType, base64.StdEncoding.EncodeToString(c.Data))},
+			}}
+		default:
+			return nil, fmt.Errorf("sampling endpoint does not support %T messages", c)
+		}
+		req.Messages = append(req.Messages, chatMessage{Role: string(msg.Role), Content: content})
+	}
+	return req, nil
+}
+
+// stopReason maps an OpenAI finish reason onto an MCP stop reason.
+func stopReason(finishReason string) string {
+	switch finishReason {
+	case "stop":
+		return "endTurn"
+	case "length":
+		return "maxTokens"
+	}
+	return finishReason
+}
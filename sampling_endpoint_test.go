@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// useSamplingEndpoint points --sampling-endpoint at a stand-in server for the
+// duration of a test.
+func useSamplingEndpoint(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	oldEndpoint, oldModel, oldKeyEnv := samplingEndpoint, samplingModel, samplingKeyEnv
+	samplingEndpoint, samplingModel, samplingKeyEnv = srv.URL+"/v1/", "fallback-model", "TEST_SAMPLING_KEY"
+	t.Cleanup(func() { samplingEndpoint, samplingModel, samplingKeyEnv = oldEndpoint, oldModel, oldKeyEnv })
+	t.Setenv("TEST_SAMPLING_KEY", "test-key")
+}
+
+func TestForwardCreateMessage(t *testing.T) {
+	var got map[string]any
+	useSamplingEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/chat/completions" {
+			t.Errorf("request = %s %s, want POST /v1/chat/completions", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer test-key" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid request body %s: %v", body, err)
+		}
+		io.WriteString(w, `{"model": "gpt-x-2024", "choices": [{"message": {"role": "assistant", "content": "Hi there"}, "finish_reason": "length"}]}`)
+	})
+
+	result, err := forwardCreateMessage(context.Background(), &mcp.CreateMessageParams{
+		MaxTokens:        100,
+		SystemPrompt:     "be nice",
+		Temperature:      0.5,
+		StopSequences:    []string{"END"},
+		ModelPreferences: &mcp.ModelPreferences{Hints: []*mcp.ModelHint{{Name: "gpt-x"}, {Name: "other"}}},
+		Messages: []*mcp.SamplingMessage{
+			{Role: "user", Content: &mcp.TextContent{Text: "hello?"}},
+			{Role: "assistant", Content: &mcp.TextContent{Text: "hi"}},
+			{Role: "user", Content: &mcp.ImageContent{MIMEType: "image/png", Data: []byte("png")}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"model": "gpt-x",
+		"messages": []any{
+			map[string]any{"role": "system", "content": "be nice"},
+			map[string]any{"role": "user", "content": "hello?"},
+			map[string]any{"role": "assistant", "content": "hi"},
+			map[string]any{"role": "user", "content": []any{
+				map[string]any{"type": "image_url", "image_url": map[string]any{"url": "data:image/png;base64,cG5n"}},
+			}},
+		},
+		"max_tokens":  float64(100),
+		"temperature": 0.5,
+		"stop":        []any{"END"},
+	}
+	gotJSON, _ := json.Marshal(got)
+	wantJSON, _ := json.Marshal(want)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("request body:\n got %s\nwant %s", gotJSON, wantJSON)
+	}
+
+	text, ok := result.Content.(*mcp.TextContent)
+	if !ok || text.Text != "Hi there" {
+		t.Errorf("content = %#v, want text %q", result.Content, "Hi there")
+	}
+	if result.Model != "gpt-x-2024" || result.Role != "assistant" || result.StopReason != "maxTokens" {
+		t.Errorf("result = model %q, role %q, stop reason %q", result.Model, result.Role, result.StopReason)
+	}
+}
+
+func TestForwardCreateMessageFallbackModel(t *testing.T) {
+	var model string
+	useSamplingEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+		var req chatRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		model = req.Model
+		io.WriteString(w, `{"model": "m", "choices": [{"message": {"content": "ok"}, "finish_reason": "stop"}]}`)
+	})
+
+	result, err := forwardCreateMessage(context.Background(), &mcp.CreateMessageParams{
+		MaxTokens: 10,
+		Messages:  []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "x"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model != "fallback-model" {
+		t.Errorf("model = %q, want the --sampling-model value", model)
+	}
+	if result.StopReason != "endTurn" {
+		t.Errorf("stop reason = %q, want endTurn", result.StopReason)
+	}
+}
+
+func TestForwardCreateMessageWithoutKeyEnv(t *testing.T) {
+	var auth []string
+	useSamplingEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Values("Authorization")
+		io.WriteString(w, `{"model": "m", "choices": [{"message": {"content": "ok"}, "finish_reason": "stop"}]}`)
+	})
+	samplingKeyEnv = ""
+	t.Setenv("OPENAI_API_KEY", "ambient-key")
+
+	_, err := forwardCreateMessage(context.Background(), &mcp.CreateMessageParams{
+		Messages: []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "x"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(auth) > 0 {
+		t.Errorf("Authorization = %q, want none without --sampling-api-key-env", auth)
+	}
+}
+
+func TestForwardCreateMessageErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"non-2xx", http.StatusTooManyRequests, `{"error": "slow down"}`, "429"},
+		{"no choices", http.StatusOK, `{"model": "m", "choices": []}`, "no choices"},
+		{"invalid JSON", http.StatusOK, `not json`, "invalid sampling endpoint response"},
+		{"unset key", http.StatusOK, `{}`, "TEST_SAMPLING_KEY is not set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSamplingEndpoint(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			})
+			if tt.name == "unset key" {
+				t.Setenv("TEST_SAMPLING_KEY", "")
+			}
+			_, err := forwardCreateMessage(context.Background(), &mcp.CreateMessageParams{
+				Messages: []*mcp.SamplingMessage{{Role: "user", Content: &mcp.TextContent{Text: "x"}}},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want one containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestToChatRequestUnsupportedContent(t *testing.T) {
+	_, err := toChatRequest(&mcp.CreateMessageParams{
+		Messages: []*mcp.SamplingMessage{{Role: "user", Content: &mcp.AudioContent{MIMEType: "audio/wav", Data: []byte("x")}}},
+	})
+	if err == nil {
+		t.Error("toChatRequest accepted audio content")
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// serverConfig is a named server in the servers config file.
+type serverConfig struct {
+	Transport string            `yaml:"transport,omitempty"` // stdio, sse or http; guessed from command or url if empty
+	Command   string            `yaml:"command,omitempty"`
+	Args      []string          `yaml:"args,omitempty"`
+	URL       string            `yaml:"url,omitempty"`
+	Env       map[string]string `yaml:"env,omitempty"`
+	Headers   map[string]string `yaml:"headers,omitempty"`
+	EnvFile   string            `yaml:"envFile,omitempty"`
+	CleanEnv  bool              `yaml:"cleanEnv,omitempty"`
+	Cwd       string            `yaml:"cwd,omitempty"`
+	Roots     []string          `yaml:"roots,omitempty"` // path[:name], as for --root
+
+	name     string
+	imported bool  // read from an editor's config file, whose values are used as is
+	problem  error // why an imported server cannot be started
+}
+
+var connectCmd = &cobra.Command{
+	Use:   "connect [name]",
+	Short: "Connect to a server defined in the servers config file",
+	Long: `Connect to a named server from the servers config file and start the TUI.
+
+The config file defaults to servers.yaml in the mcp-cli directory under the user
+config directory, for example ~/.config/mcp-cli/servers.yaml. Each server has a
+transport, a command and args or a URL, and optionally env, headers, cwd and
+roots. Values in env and headers may refer to environment variables as $NAME or
+${NAME}. Without a name, a list of the configured servers is shown to pick from.
+
+With --from-config, the servers are read from a Claude Desktop
+(claude_desktop_config.json) or VS Code (.vscode/mcp.json or settings.json)
+config file instead, so the server is launched exactly as the editor would.`,
+	Example: `  mcp-cli connect files
+  mcp-cli connect --config ./servers.yaml staging
+  mcp-cli connect --from-config .vscode/mcp.json --server files`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path, _ := cmd.Flags().GetString("config")
+		editorPath, _ := cmd.Flags().GetString("from-config")
+		name, _ := cmd.Flags().GetString("server")
+		if len(args) == 1 {
+			if name != "" && name != args[0] {
+				log.Fatalf("Give the server name either as an argument or with --server, not both")
+			}
+			name = args[0]
+		}
+
+		var servers map[string]*serverConfig
+		var err error
+		switch {
+		case editorPath != "":
+			path = editorPath
+			servers, err = loadEditorServers(path)
+		case path == "":
+			if path, err = serversConfigPath(); err != nil {
+				log.Fatalf("Failed to find the servers config file: %v", err)
+			}
+			fallthrough
+		default:
+			servers, err = loadServers(path)
+		}
+		if err != nil {
+			log.Fatalf("Failed to load servers: %v", err)
+		}
+		if len(servers) == 0 {
+			log.Fatalf("No servers defined in %s", path)
+		}
+
+		var server *serverConfig
+		if name != "" {
+			server = servers[name]
+			if server == nil {
+				log.Fatalf("No server named %q in %s", name, path)
+			}
+		} else {
+			server, err = pickServer(servers)
+			if err != nil {
+				log.Fatalf("Failed to pick a server: %v", err)
+			}
+			if server == nil {
+				return
+			}
+		}
+
+		extraRoots, err := rootsFromFlags(cmd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		connectServer(server, extraRoots)
+	},
+}
+
+// serversConfigPath returns the default servers config file.
+func serversConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mcp-cli", "servers.yaml"), nil
+}
+
+// loadServers reads the servers config file at path.
+func loadServers(path string) (map[string]*serverConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Servers map[string]*serverConfig `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for name, s := range file.Servers {
+		if s == nil {
+			return nil, fmt.Errorf("%s: server %q is empty", path, name)
+		}
+		s.name = name
+		if err := s.check(); err != nil {
+			return nil, fmt.Errorf("%s: server %q: %w", path, name, err)
+		}
+	}
+	return file.Servers, nil
+}
+
+// kind returns the transport of the server, guessing it from the command or
+// URL if it is not given.
+func (s *serverConfig) kind() string {
+	switch {
+	case s.Transport != "":
+		return s.Transport
+	case s.Command != "":
+		return "stdio"
+	case s.URL != "":
+		return "http"
+	}
+	return ""
+}
+
+func (s *serverConfig) check() error {
+	switch s.kind() {
+	case "stdio":
+		argv, err := s.argv()
+		if err != nil {
+			return err
+		}
+		if len(argv) == 0 {
+			return fmt.Errorf("stdio server has no command")
+		}
+	case "sse", "http":
+		if s.URL == "" {
+			return fmt.Errorf("%s server has no url", s.kind())
+		}
+	case "":
+		return fmt.Errorf("no command or url")
+	default:
+		return fmt.Errorf("unknown transport %q (want stdio, sse or http)", s.Transport)
+	}
+	return nil
+}
+
+// argv returns the command line of a stdio server: the words of command,
+// split with shell-style quoting, followed by args.
+func (s *serverConfig) argv() ([]string, error) {
+	argv, err := splitCommand(s.Command)
+	if err != nil {
+		return nil, err
+	}
+	return append(argv, s.Args...), nil
+}
+
+// target returns the command line or URL of the server, as it would be
+// given to the stdio, sse or http command.
+func (s *serverConfig) target() string {
+	if s.kind() == "stdio" {
+		argv, _ := s.argv()
+		return quoteCommand(argv)
+	}
+	return s.URL
+}
+
+// envList returns the server's environment variables as KEY=value, with
+// references to the current environment expanded.
+func (s *serverConfig) envList() []string {
+	var env []string
+	for _, k := range slices.Sorted(maps.Keys(s.Env)) {
+		env = append(env, k+"="+s.expand(s.Env[k]))
+	}
+	return env
+}
+
+// headerList returns the server's headers as "Name: value", with references
+// to the current environment expanded.
+func (s *serverConfig) headerList() []string {
+	var headers []string
+	for _, k := range slices.Sorted(maps.Keys(s.Headers)) {
+		headers = append(headers, k+": "+s.expand(s.Headers[k]))
+	}
+	return headers
+}
+
+// expand expands references to environment variables in v. Servers imported
+// from an editor's config file have had their variables resolved already.
+func (s *serverConfig) expand(v string) string {
+	if s.imported {
+		return v
+	}
+	return os.ExpandEnv(v)
+}
+
+// dir returns the working directory of a stdio server, with a leading ~
+// expanded to the home directory.
+func (s *serverConfig) dir() string {
+	return expandHome(s.Cwd)
+}
+
+// roots parses the server's roots. Relative paths are taken relative to the
+// server's working directory, if it has one.
+func (s *serverConfig) roots() ([]*mcp.Root, error) {
+	var roots []*mcp.Root
+	for _, r := range s.Roots {
+		r = expandHome(r)
+		if dir := s.dir(); dir != "" && !filepath.IsAbs(r) {
+			r = filepath.Join(dir, r)
+		}
+		root, err := parseRoot(r)
+		if err != nil {
+			return nil, fmt.Errorf("server %q: %w", s.name, err)
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// transport builds a new transport for the server, the same way the stdio,
+// sse and http commands do.
+func (s *serverConfig) transport() (mcp.Transport, error) {
+	switch s.kind() {
+	case "stdio":
+		argv, err := s.argv()
+		if err != nil {
+			return nil, err
+		}
+		opts := stdioOptions{env: s.envList(), dir: s.dir(), cleanEnv: s.CleanEnv}
+		if s.EnvFile != "" {
+			opts.envFiles = []string{expandHome(s.EnvFile)}
+		}
+		return newCommandTransport(argv, opts)
+	case "sse":
+		return &mcp.SSEClientTransport{Endpoint: s.URL, HTTPClient: newHTTPClient(s.headerList())}, nil
+	}
+	return &mcp.StreamableClientTransport{Endpoint: s.URL, HTTPClient: newHTTPClient(s.headerList())}, nil
+}
+
+// connectServer connects to a configured server and runs the TUI. Servers
+// over sse and http are reconnected when the session drops, as with the sse
+// and http commands.
+func connectServer(s *serverConfig, extraRoots []*mcp.Root) {
+	if s.problem != nil {
+		log.Fatalf("Cannot connect to %s: %v", s.name, s.problem)
+	}
+	roots, err := s.roots()
+	if err != nil {
+		log.Fatal(err)
+	}
+	roots = append(roots, extraRoots...)
+
+	ctx := context.Background()
+	client := newClient(roots)
+	id := serverID(s.kind(), s.target())
+	if verbose {
+		log.Printf("Connecting to %s (%s %s)", s.name, s.kind(), s.target())
+	}
+
+	if s.kind() == "stdio" {
+		transport, err := s.transport()
+		if err != nil {
+			log.Fatalf("Failed to start %s: %v", s.name, err)
+		}
+		session, err := client.Connect(ctx, transport, nil)
+		if err != nil {
+			log.Fatalf("Failed to connect to %s: %v", s.name, err)
+		}
+		defer session.Close()
+		handleSession(ctx, session, id, newClientRoots(client, roots))
+		return
+	}
+	connect := func() (*mcp.ClientSession, error) {
+		transport, err := s.transport()
+		if err != nil {
+			return nil, err
+		}
+		return client.Connect(ctx, transport, nil)
+	}
+	runSessionWithReconnect(ctx, connect, id, newClientRoots(client, roots))
+}
+
+// expandHome replaces a leading ~ in path with the home directory.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+type serverItem struct {
+	server *serverConfig
+}
+
+func (i serverItem) Title() string { return i.server.name }
+func (i serverItem) Description() string {
+	if i.server.problem != nil {
+		return i.server.problem.Error()
+	}
+	return i.server.kind() + "  " + i.server.target()
+}
+
+func (i serverItem) FilterValue() string { return i.server.name }
+
+// serverPicker is a small TUI that lists the configured servers when connect
+// is run without a name.
+type serverPicker struct {
+	list   list.Model
+	chosen *serverConfig
+}
+
+func (p *serverPicker) Init() tea.Cmd { return nil }
+
+func (p *serverPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.list.SetSize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		if msg.String() == "enter" && p.list.FilterState() != list.Filtering {
+			if item, ok := p.list.SelectedItem().(serverItem); ok {
+				p.chosen = item.server
+				return p, tea.Quit
+			}
+		}
+	}
+	var cmd tea.Cmd
+	p.list, cmd = p.list.Update(msg)
+	return p, cmd
+}
+
+func (p *serverPicker) View() string { return p.list.View() }
+
+// pickServer asks the user to choose one of servers. It returns nil if the
+// user quits without choosing.
+func pickServer(servers map[string]*serverConfig) (*serverConfig, error) {
+	items := []list.Item{}
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		items = append(items, serverItem{server: servers[name]})
+	}
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Servers"
+
+	final, err := tea.NewProgram(&serverPicker{list: l}, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
+	}
+	return final.(*serverPicker).chosen, nil
+}
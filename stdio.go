@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// stdioOptions are the settings for launching a stdio server besides its
+// command line.
+type stdioOptions struct {
+	env      []string // KEY=value, added to the inherited environment
+	envFiles []string // read before env, which overrides them
+	dir      string   // working directory; empty means the current one
+	cleanEnv bool     // do not inherit the environment of mcp-cli
+}
+
+// environ returns the environment of the server process.
+func (o stdioOptions) environ() ([]string, error) {
+	// A nil Env makes exec.Cmd inherit the environment, so a clean one
+	// must be empty but not nil.
+	env := []string{}
+	if !o.cleanEnv {
+		env = os.Environ()
+	}
+	for _, path := range o.envFiles {
+		vars, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, vars...)
+	}
+	return append(env, o.env...), nil
+}
+
+// addStdioFlags registers the flags that control how a stdio server is
+// launched.
+func addStdioFlags(cmd *cobra.Command) {
+	cmd.Flags().String("cwd", "", "Working directory of the server command (stdio)")
+	cmd.Flags().StringArray("env-file", []string{}, "File of KEY=value lines to add to the server's environment (stdio, repeatable)")
+	cmd.Flags().Bool("clean-env", false, "Do not pass the environment of mcp-cli on to the server command (stdio)")
+}
+
+// stdioOptionsFromFlags reads the flags registered by addStdioFlags and the
+// --env flag.
+func stdioOptionsFromFlags(cmd *cobra.Command) stdioOptions {
+	var o stdioOptions
+	o.env, _ = cmd.Flags().GetStringSlice("env")
+	o.envFiles, _ = cmd.Flags().GetStringArray("env-file")
+	o.dir, _ = cmd.Flags().GetString("cwd")
+	o.cleanEnv, _ = cmd.Flags().GetBool("clean-env")
+	return o
+}
+
+// splitCommand splits a command line into words the way a POSIX shell does,
+// without expanding variables or globs. Single quotes keep everything
+// literally, double quotes keep everything except backslash escapes of ",
+// \, $ and `, and a backslash outside quotes escapes the next character.
+func splitCommand(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated ' in %q", s)
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`", s[i+1]) >= 0 {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated \" in %q", s)
+			}
+			inWord = true
+		case c == '\\':
+			if i+1 < len(s) {
+				i++
+				word.WriteByte(s[i])
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+var safeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// quoteCommand joins argv into a command line that splitCommand turns back
+// into argv, quoting only the words that need it.
+func quoteCommand(argv []string) string {
+	words := make([]string, len(argv))
+	for i, arg := range argv {
+		if safeWord.MatchString(arg) {
+			words[i] = arg
+		} else {
+			words[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// readEnvFile reads KEY=value lines from a .env style file. Blank lines,
+// lines starting with # and a leading "export " are ignored. Values may be
+// quoted: single-quoted values are kept as they are, and double-quoted values
+// may use \n, \" and \\ escapes.
+func readEnvFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var env []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s:%d: expected KEY=value", path, n)
+		}
+		value = strings.TrimSpace(value)
+		switch {
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			value = value[1 : len(value)-1]
+		case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+			value = strings.NewReplacer(`\n`, "\n", `\"`, `"`, `\\`, `\`).Replace(value[1 : len(value)-1])
+		}
+		env = append(env, key+"="+value)
+	}
+	return env, scanner.Err()
+}
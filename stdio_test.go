@@ -0,0 +1,138 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"python server.py", []string{"python", "server.py"}},
+		{"  a \t b\nc  ", []string{"a", "b", "c"}},
+		{`'/path/with spaces/server' --x`, []string{"/path/with spaces/server", "--x"}},
+		{`"arg two" x\ y`, []string{"arg two", "x y"}},
+		{`'it''s'`, []string{"its"}},
+		{`'a\b'`, []string{`a\b`}},
+		{`"a \"b\" \\ \$HOME \n"`, []string{`a "b" \ $HOME \n`}},
+		{`pre"mid"'post'`, []string{"premidpost"}},
+		{`'' ""`, []string{"", ""}},
+		{`a\`, []string{"a"}},
+		{`$HOME *.go`, []string{"$HOME", "*.go"}},
+	}
+	for _, tt := range tests {
+		got, err := splitCommand(tt.in)
+		if err != nil {
+			t.Errorf("splitCommand(%q) error: %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitCommand(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{`'open`, `"open`, `a "b\"`} {
+		if _, err := splitCommand(in); err == nil {
+			t.Errorf("splitCommand(%q) succeeded, want an error", in)
+		}
+	}
+}
+
+func TestQuoteCommandRoundTrip(t *testing.T) {
+	argvs := [][]string{
+		{"python", "server.py"},
+		{"/path/with spaces/server", "it's", `"quoted"`, `back\slash`, "$HOME", ""},
+	}
+	for _, argv := range argvs {
+		got, err := splitCommand(quoteCommand(argv))
+		if err != nil || !slices.Equal(got, argv) {
+			t.Errorf("splitCommand(quoteCommand(%q)) = %q, %v", argv, got, err)
+		}
+	}
+	if got := quoteCommand([]string{"python", "server.py", "--port=8080"}); got != "python server.py --port=8080" {
+		t.Errorf("quoteCommand quoted safe words: %s", got)
+	}
+}
+
+func TestReadEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	content := `# a comment
+
+export A=plain
+B = spaced
+C="two\nlines \"quoted\" \\"
+D='$literal \n'
+E=
+F=a=b
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readEnvFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"A=plain",
+		"B=spaced",
+		"C=two\nlines \"quoted\" \\",
+		`D=$literal \n`,
+		"E=",
+		"F=a=b",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("readEnvFile = %q, want %q", got, want)
+	}
+
+	if err := os.WriteFile(path, []byte("A=1\nnot a variable\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readEnvFile(path); err == nil {
+		t.Error("readEnvFile accepted a line without =")
+	}
+}
+
+func TestEnvironCleanEnv(t *testing.T) {
+	t.Setenv("MCP_CLI_TEST_PARENT", "1")
+
+	env, err := stdioOptions{cleanEnv: true}.environ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// exec.Cmd treats a nil Env as "inherit the parent's environment".
+	if env == nil || len(env) != 0 {
+		t.Errorf("clean environment = %q, want an empty non-nil slice", env)
+	}
+
+	env, err = stdioOptions{cleanEnv: true, env: []string{"A=1"}}.environ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(env, []string{"A=1"}) {
+		t.Errorf("clean environment with --env = %q", env)
+	}
+
+	env, err = stdioOptions{}.environ()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(env, "MCP_CLI_TEST_PARENT=1") {
+		t.Error("environment does not inherit the parent's variables")
+	}
+}
+
+func TestCommandTransportCleanEnv(t *testing.T) {
+	transport, err := newCommandTransport([]string{"true"}, stdioOptions{cleanEnv: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transport.Command.Env == nil {
+		t.Error("--clean-env left Env nil, so the command inherits the environment")
+	}
+}
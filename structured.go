@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	schemaOKStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))  // Green
+	schemaErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // Red
+	resultHeadStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+// structuredResult is the structured content of a tool result, checked
+// against the tool's output schema and compared with the text content.
+type structuredResult struct {
+	content    string   // indented StructuredContent, or "" if there is none
+	hasSchema  bool     // whether the tool declares an output schema
+	violations []string // ways in which the result breaks the output schema
+	warnings   []string // differences between the text and structured content
+}
+
+// checkStructuredContent validates the structured content of result against
+// the output schema of tool. A tool with an output schema must return
+// matching structured content unless the result is an error, and should also
+// return the same JSON as text.
+func checkStructuredContent(tool *mcp.Tool, result *mcp.CallToolResult) structuredResult {
+	var s structuredResult
+	s.hasSchema = tool != nil && tool.OutputSchema != nil
+
+	if result.StructuredContent == nil {
+		if s.hasSchema && !result.IsError {
+			s.violations = append(s.violations, "tool declares an output schema but returned no structured content")
+		}
+		return s
+	}
+
+	// Round-trip through JSON so that the value has plain JSON types.
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		s.violations = append(s.violations, fmt.Sprintf("structured content is not valid JSON: %v", err))
+		return s
+	}
+	var value any
+	json.Unmarshal(data, &value)
+	pretty, _ := json.MarshalIndent(value, "", "  ")
+	s.content = string(pretty)
+
+	if _, ok := value.(map[string]any); !ok {
+		s.violations = append(s.violations, "structured content is not a JSON object")
+	}
+	if s.hasSchema {
+		resolved, err := resolveSchema(tool.OutputSchema)
+		if err != nil {
+			s.warnings = append(s.warnings, fmt.Sprintf("cannot validate against output schema: %v", err))
+		} else if err := resolved.Validate(value); err != nil {
+			s.violations = append(s.violations, err.Error())
+		}
+	}
+
+	if !textMatches(result.Content, value) {
+		s.warnings = append(s.warnings, "no text content holds the same JSON as the structured content")
+	}
+	return s
+}
+
+// textMatches reports whether some text content parses to value.
+func textMatches(content []mcp.Content, value any) bool {
+	for _, c := range content {
+		text, ok := c.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		var parsed any
+		if json.Unmarshal([]byte(text.Text), &parsed) == nil && reflect.DeepEqual(parsed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// summary returns one line per violation or warning, or a confirmation that
+// the structured content matches the output schema.
+func (s structuredResult) summary() []string {
+	var lines []string
+	for _, v := range s.violations {
+		lines = append(lines, "Output schema violation: "+v)
+	}
+	for _, w := range s.warnings {
+		lines = append(lines, "Warning: "+w)
+	}
+	if len(s.violations) == 0 && s.hasSchema && s.content != "" {
+		lines = append(lines, "Matches output schema")
+	}
+	return lines
+}
+
+// view renders the structured content with its schema status.
+func (s structuredResult) view() string {
+	var b strings.Builder
+	b.WriteString(resultHeadStyle.Render("Structured content"))
+	b.WriteString("\n")
+	for _, line := range s.summary() {
+		style := schemaOKStyle
+		if strings.HasPrefix(line, "Output schema violation") {
+			style = schemaErrorStyle
+		} else if strings.HasPrefix(line, "Warning") {
+			style = callStatusStyle
+		}
+		b.WriteString(style.Render(line))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	if s.content == "" {
+		b.WriteString("(none)")
+	} else {
+		b.WriteString(s.content)
+	}
+	return b.String()
+}
+
+// shown reports whether there is structured content or a violation to show
+// next to the text content.
+func (s structuredResult) shown() bool {
+	return s.content != "" || len(s.violations) > 0
+}
+
+// renderToolResult renders a tool result with the text content and, when there
+// is any, the structured content side by side. A width of zero leaves the
+// lines unwrapped.
+func renderToolResult(text string, s structuredResult, width int) string {
+	content := resultHeadStyle.Render("Content") + "\n\n" + text
+	if !s.shown() {
+		return lipgloss.NewStyle().Width(width).Render(content)
+	}
+	colWidth := max((width-2)/2, 0)
+	left := lipgloss.NewStyle().Width(colWidth).MarginRight(2).Render(content)
+	right := lipgloss.NewStyle().Width(colWidth).Render(s.view())
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))  // Green
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // Red
+)
+
+// subscriptionResult represents the outcome of a subscribe or unsubscribe request
+type subscriptionResult struct {
+	uri        string
+	subscribed bool
+	err        error
+}
+
+func (m *AppModel) updateResourceDetailView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "s" {
+		if !m.canSubscribe() {
+			m.logf("Server does not support resource subscriptions")
+			return m, nil
+		}
+		return m, m.toggleSubscriptionCmd()
+	}
+	return m, nil
+}
+
+// canSubscribe reports whether the server advertises resources.subscribe.
+func (m *AppModel) canSubscribe() bool {
+	init := m.session.InitializeResult()
+	return init != nil && init.Capabilities != nil &&
+		init.Capabilities.Resources != nil && init.Capabilities.Resources.Subscribe
+}
+
+// toggleSubscriptionCmd returns a tea.Cmd that subscribes to the selected
+// resource, or unsubscribes if a subscription is already active.
+func (m *AppModel) toggleSubscriptionCmd() tea.Cmd {
+	uri := m.selectedResource.URI
+	subscribed := m.subscriptions[uri]
+	session, ctx := m.session, m.ctx
+	return func() tea.Msg {
+		var err error
+		if subscribed {
+			err = session.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: uri})
+		} else {
+			err = session.Subscribe(ctx, &mcp.SubscribeParams{URI: uri})
+		}
+		return subscriptionResult{uri: uri, subscribed: !subscribed, err: err}
+	}
+}
+
+// diffText renders resource contents for a diff: the metadata of each content
+// as JSON on one line, followed by its text as is. A changed line of text is
+// then one changed line of the diff, rather than a change to one long escaped
+// JSON string. Blobs are summarized by their size and hash.
+func diffText(contents []*mcp.ResourceContents) string {
+	var b strings.Builder
+	for _, c := range contents {
+		meta := map[string]any{"uri": c.URI}
+		if c.MIMEType != "" {
+			meta["mimeType"] = c.MIMEType
+		}
+		if c.Blob != nil {
+			meta["blob"] = fmt.Sprintf("%d bytes, sha256 %x", len(c.Blob), sha256.Sum256(c.Blob))
+		}
+		line, _ := json.Marshal(meta)
+		b.Write(line)
+		b.WriteByte('\n')
+		if c.Text != "" {
+			b.WriteString(strings.TrimSuffix(c.Text, "\n"))
+			b.WriteByte('\n')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderDiff returns a line diff from old to new, with added lines in green
+// and removed lines in red.
+func renderDiff(old, new string) string {
+	a := strings.Split(old, "\n")
+	b := strings.Split(new, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffRemovedStyle.Render("- "+a[i]))
+			i++
+		default:
+			lines = append(lines, diffAddedStyle.Render("+ "+b[j]))
+			j++
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+func (m *AppModel) updateTemplateListView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.templateList, cmd = m.templateList.Update(msg)
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case "t":
+			m.state = toolSelectionView
+			return m, nil
+		case "r":
+			m.state = resourceListView
+			return m, nil
+		case "p":
+			m.state = promptListView
+			return m, nil
+		case "enter":
+			selectedItem, ok := m.templateList.SelectedItem().(templateItem)
+			if !ok {
+				return m, nil
+			}
+			m.selectedTemplate = selectedItem.template
+
+			tmpl, err := uritemplate.New(m.selectedTemplate.URITemplate)
+			if err != nil {
+				m.logf("Invalid URI template '%s': %v", m.selectedTemplate.URITemplate, err)
+				return m, nil
+			}
+
+			m.templateVars = tmpl.Varnames()
+			if len(m.templateVars) == 0 {
+				return m.readTemplate()
+			}
+
+			if verbose {
+				m.logf("State change: templateListView -> templateArgumentInputView")
+			}
+			m.state = templateArgumentInputView
+			m.templateInputs = []textinput.Model{}
+			m.templateFocus = 0
+			for range m.templateVars {
+				ti := textinput.New()
+				ti.CharLimit = 256
+				ti.Width = 50
+				m.templateInputs = append(m.templateInputs, ti)
+			}
+			m.templateInputs[0].Focus()
+			return m, nil
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *AppModel) updateTemplateArgumentInputView(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		if m.templateFocus == len(m.templateInputs)-1 {
+			return m.readTemplate()
+		}
+		m.templateFocus++
+		focusInput(m.templateInputs, m.templateFocus)
+		return m, nil
+
+	case tea.KeyTab:
+		m.templateFocus = (m.templateFocus + 1) % len(m.templateInputs)
+		focusInput(m.templateInputs, m.templateFocus)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.templateInputs[m.templateFocus], cmd = m.templateInputs[m.templateFocus].Update(msg)
+	return m, cmd
+}
+
+// readTemplate expands the selected template with the entered variables and
+// reads the resulting URI in the resource detail view. Empty variables are
+// left undefined, so optional query parameters are dropped.
+func (m *AppModel) readTemplate() (tea.Model, tea.Cmd) {
+	tmpl, err := uritemplate.New(m.selectedTemplate.URITemplate)
+	if err != nil {
+		m.logf("Invalid URI template '%s': %v", m.selectedTemplate.URITemplate, err)
+		return m, nil
+	}
+
+	values := uritemplate.Values{}
+	for i, name := range m.templateVars {
+		if value := m.templateInputs[i].Value(); value != "" {
+			values.Set(name, uritemplate.String(value))
+		}
+	}
+	uri, err := tmpl.Expand(values)
+	if err != nil {
+		m.logf("Failed to expand URI template '%s': %v", m.selectedTemplate.URITemplate, err)
+		return m, nil
+	}
+	m.logf("Expanded %s to %s", m.selectedTemplate.URITemplate, uri)
+
+	m.selectedResource = &mcp.Resource{
+		Name:     m.selectedTemplate.Name,
+		URI:      uri,
+		MIMEType: m.selectedTemplate.MIMEType,
+	}
+	m.resourceResult = ""
+	m.resourceContents = nil
+	m.state = resourceDetailView
+	return m, m.readResourceCmd()
+}
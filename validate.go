@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+var fieldErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")) // Red
+
+// resolveSchema prepares schema for validation. The validator only supports
+// draft 2020-12, so the $schema keyword of the root is ignored; tool schemas
+// rarely rely on differences between drafts.
+func resolveSchema(schema *jsonschema.Schema) (*jsonschema.Resolved, error) {
+	s := *schema
+	s.Schema = ""
+	return s.Resolve(nil)
+}
+
+// validateArgs checks args against schema. A schema that cannot be resolved,
+// for example because it refers to a remote document, is reported as an error.
+func validateArgs(schema *jsonschema.Schema, args map[string]any) error {
+	if schema == nil {
+		return nil
+	}
+	resolved, err := resolveSchema(schema)
+	if err != nil {
+		return fmt.Errorf("cannot validate against input schema: %w", err)
+	}
+	if err := resolved.Validate(args); err != nil {
+		return fmt.Errorf("arguments do not match input schema: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the form against schema, recording an error on each field
+// that is missing, cannot be converted or does not match its property schema.
+// It focuses the first invalid field and reports whether the form is valid.
+func (f *argForm) Validate(schema *jsonschema.Schema) bool {
+	f.err = ""
+	valid := true
+	for _, field := range f.fields {
+		if !field.validate(field.name) {
+			valid = false
+		}
+	}
+	if valid {
+		args, _ := f.Value()
+		if err := validateArgs(schema, args); err != nil {
+			f.err = err.Error()
+			return false
+		}
+		return true
+	}
+
+	for i, row := range f.rows() {
+		if row.field.err != "" {
+			f.focus = i
+			f.refocus()
+			break
+		}
+	}
+	return false
+}
+
+// validate checks the field and its nested fields, and reports whether they
+// are valid.
+func (f *formField) validate(path string) bool {
+	f.err = ""
+	v, set, err := f.value(path)
+	if !set {
+		f.clearErrors()
+		if f.required && !f.isItem {
+			f.err = "required"
+			return false
+		}
+		return true
+	}
+	if err != nil && f.kind == textField {
+		f.err = fmt.Sprintf("not a valid %s", schemaType(f.schema))
+		return false
+	}
+
+	valid := true
+	for i, child := range f.children {
+		childPath := path + "." + child.name
+		if f.kind == arrayField {
+			childPath = fmt.Sprintf("%s[%d]", path, i)
+		}
+		if !child.validate(childPath) {
+			valid = false
+		}
+	}
+	if !valid {
+		return false
+	}
+
+	resolved, err := resolveSchema(f.schema)
+	if err != nil {
+		// Property schemas that use $ref can only be checked as part of the
+		// whole input schema.
+		return true
+	}
+	if err := resolved.Validate(v); err != nil {
+		f.err = err.Error()
+		return false
+	}
+	return true
+}
+
+func (f *formField) clearErrors() {
+	f.err = ""
+	for _, child := range f.children {
+		child.clearErrors()
+	}
+}